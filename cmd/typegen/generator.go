@@ -0,0 +1,394 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const systemTypeCodePrefix = "http://hl7.org/fhirpath/"
+const choiceSuffix = "[x]"
+
+type packageManifest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type structureDefinition struct {
+	ResourceType   string              `json:"resourceType"`
+	URL            string              `json:"url"`
+	Kind           string              `json:"kind"`
+	Type           string              `json:"type"`
+	BaseDefinition string              `json:"baseDefinition"`
+	Derivation     string              `json:"derivation"`
+	Snapshot       *elementDefinitions `json:"snapshot"`
+	Differential   *elementDefinitions `json:"differential"`
+}
+
+type elementDefinitions struct {
+	Element []elementDefinition `json:"element"`
+}
+
+type elementDefinition struct {
+	ID               string        `json:"id"`
+	Path             string        `json:"path"`
+	Min              int           `json:"min"`
+	Max              string        `json:"max"`
+	Base             *elementBase  `json:"base"`
+	Type             []elementType `json:"type"`
+	ContentReference string        `json:"contentReference"`
+	IsModifier       bool          `json:"isModifier"`
+	IsSummary        bool          `json:"isSummary"`
+}
+
+type elementBase struct {
+	Path string `json:"path"`
+}
+
+type elementType struct {
+	Code string `json:"code"`
+}
+
+type typeDefinition struct {
+	name       string
+	base       string
+	source     string
+	properties []*propertyDefinition
+}
+
+type propertyDefinition struct {
+	name     string
+	types    []string
+	min      int
+	max      int
+	modifier bool
+	summary  bool
+}
+
+type model struct {
+	source string
+	types  map[string]*typeDefinition
+}
+
+func newModel() *model {
+	return &model{types: make(map[string]*typeDefinition)}
+}
+
+func loadPackage(dir string) (*model, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m := newModel()
+	m.source = filepath.Base(dir)
+	if content, err := ioutil.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var manifest packageManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("%s: %v", filepath.Join(dir, "package.json"), err)
+		}
+		if manifest.Name != "" {
+			m.source = manifest.Name + "#" + manifest.Version
+		}
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") || f.Name() == "package.json" {
+			continue
+		}
+
+		path := filepath.Join(dir, f.Name())
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var sd structureDefinition
+		if err := json.Unmarshal(content, &sd); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if err := m.add(&sd); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return m, nil
+}
+
+func (m *model) add(sd *structureDefinition) error {
+	if sd.ResourceType != "StructureDefinition" || sd.Kind == "logical" || sd.Derivation == "constraint" {
+		return nil
+	}
+	if sd.Type == "" {
+		return fmt.Errorf("structure definition without type: %s", sd.URL)
+	}
+	if _, found := m.types[sd.Type]; found {
+		return fmt.Errorf("type has already been defined: %s", sd.Type)
+	}
+
+	m.types[sd.Type] = &typeDefinition{
+		name:   sd.Type,
+		base:   sd.BaseDefinition[strings.LastIndexByte(sd.BaseDefinition, '/')+1:],
+		source: sd.Type,
+	}
+
+	elements := sd.Differential
+	if sd.Snapshot != nil {
+		elements = sd.Snapshot
+	}
+	if elements == nil {
+		return nil
+	}
+
+	parents := make(map[string]bool)
+	for _, e := range elements.Element {
+		if i := strings.LastIndexByte(e.Path, '.'); i >= 0 {
+			parents[e.Path[:i]] = true
+		}
+	}
+
+	for _, e := range elements.Element {
+		i := strings.LastIndexByte(e.Path, '.')
+		if i < 0 || strings.IndexByte(e.ID, ':') >= 0 || !m.declared(sd.Type, &e) {
+			continue
+		}
+
+		owner := m.types[e.Path[:i]]
+		if owner == nil {
+			return fmt.Errorf("element without parent: %s", e.Path)
+		}
+		p, err := newPropertyDefinition(&e, e.Path[i+1:])
+		if err != nil {
+			return err
+		}
+		if parents[e.Path] && e.ContentReference == "" {
+			if len(e.Type) != 1 {
+				return fmt.Errorf("backbone element without single type: %s", e.Path)
+			}
+			m.types[e.Path] = &typeDefinition{
+				name:   e.Path,
+				base:   e.Type[0].Code,
+				source: sd.Type,
+			}
+			p.types = []string{e.Path}
+		}
+		owner.properties = append(owner.properties, p)
+	}
+	return nil
+}
+
+func (m *model) declared(typeName string, e *elementDefinition) bool {
+	if e.Base == nil || e.Base.Path == "" {
+		return true
+	}
+	base := e.Base.Path
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	return base == typeName
+}
+
+func newPropertyDefinition(e *elementDefinition, name string) (*propertyDefinition, error) {
+	p := &propertyDefinition{
+		name:     name,
+		min:      e.Min,
+		modifier: e.IsModifier,
+		summary:  e.IsSummary,
+	}
+
+	switch e.Max {
+	case "*":
+		p.max = -1
+	case "":
+		p.max = 1
+	default:
+		max, err := strconv.Atoi(e.Max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maximum cardinality of %s: %s", e.Path, e.Max)
+		}
+		p.max = max
+	}
+
+	if e.ContentReference != "" {
+		p.types = []string{e.ContentReference[strings.IndexByte(e.ContentReference, '#')+1:]}
+		return p, nil
+	}
+	for _, t := range e.Type {
+		code := t.Code
+		if strings.HasPrefix(code, systemTypeCodePrefix) {
+			code = code[len(systemTypeCodePrefix):]
+		}
+		p.types = append(p.types, code)
+	}
+	if len(p.types) == 0 {
+		return nil, fmt.Errorf("element without type: %s", e.Path)
+	}
+	return p, nil
+}
+
+func (m *model) generate(packageName string) ([]byte, error) {
+	names := make([]string, 0, len(m.types))
+	for name := range m.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	systemTypes := make(map[string]bool)
+	for _, name := range names {
+		t := m.types[name]
+		if t.base != "" && m.types[t.base] == nil {
+			return nil, fmt.Errorf("base type %s of %s has not been defined", t.base, name)
+		}
+		for _, p := range t.properties {
+			for _, pt := range p.types {
+				if strings.HasPrefix(pt, "System.") {
+					systemTypes[pt] = true
+				} else if m.types[pt] == nil {
+					return nil, fmt.Errorf("type %s of %s.%s has not been defined", pt, name, p.name)
+				}
+			}
+		}
+	}
+	systemNames := make([]string, 0, len(systemTypes))
+	for name := range systemTypes {
+		systemNames = append(systemNames, name)
+	}
+	sort.Strings(systemNames)
+
+	identifiers := make(map[string]string)
+	for _, name := range append(systemNames, names...) {
+		identifier := typeSpecIdentifier(name)
+		if other, found := identifiers[identifier]; found {
+			return nil, fmt.Errorf("types %s and %s result in the same identifier %s", other, name, identifier)
+		}
+		identifiers[identifier] = name
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by typegen from %s; DO NOT EDIT.\n\n", m.source)
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	b.WriteString("import \"github.com/healthiop/hi/datatype\"\n\n")
+
+	b.WriteString("var (\n")
+	for _, name := range systemNames {
+		fmt.Fprintf(&b, "%s = mustLookupTypeSpec(%q)\n", typeSpecIdentifier(name), name)
+	}
+	for _, name := range names {
+		base := "nil"
+		if t := m.types[name]; t.base != "" {
+			base = typeSpecIdentifier(t.base)
+		}
+		fmt.Fprintf(&b, "%s = typeSpec(%q, %s)\n", typeSpecIdentifier(name), name, base)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("func init() {\n")
+	for _, name := range names {
+		t := m.types[name]
+		if len(t.properties) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "setProperties(%s,\n", typeSpecIdentifier(name))
+		for _, p := range t.properties {
+			fmt.Fprintf(&b, "datatype.NewTypeSpecProperty(%q, %d, %s, %s", p.name, p.min, maxCardinality(p.max), propertyFlags(p))
+			for _, pt := range p.types {
+				fmt.Fprintf(&b, ", %s", typeSpecIdentifier(pt))
+			}
+			b.WriteString("),\n")
+		}
+		b.WriteString(")\n")
+	}
+	b.WriteString("}\n\n")
+	b.WriteString(generatedHelpers)
+
+	return format.Source(b.Bytes())
+}
+
+func typeSpecIdentifier(name string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(name, ".") {
+		r, size := utf8.DecodeRuneInString(part)
+		if i == 0 {
+			r = unicode.ToLower(r)
+		} else {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+		b.WriteString(part[size:])
+	}
+	b.WriteString("TypeSpec")
+	return b.String()
+}
+
+func maxCardinality(max int) string {
+	if max < 0 {
+		return "datatype.UnboundedCardinality"
+	}
+	return strconv.Itoa(max)
+}
+
+func propertyFlags(p *propertyDefinition) string {
+	switch {
+	case p.modifier && p.summary:
+		return "datatype.ModifierPropertyFlag | datatype.SummaryPropertyFlag"
+	case p.modifier:
+		return "datatype.ModifierPropertyFlag"
+	case p.summary:
+		return "datatype.SummaryPropertyFlag"
+	}
+	return "0"
+}
+
+const generatedHelpers = `func typeSpec(name string, base datatype.TypeSpecAccessor) datatype.TypeSpecAccessor {
+	return datatype.LoadOrRegisterTypeSpec(datatype.NewTypeSpecWithBase(
+		datatype.NewFQTypeName(name, datatype.FHIRNamespaceName), base))
+}
+
+func mustLookupTypeSpec(fqName string) datatype.TypeSpecAccessor {
+	spec := datatype.LookupTypeSpec(fqName)
+	if spec == nil {
+		panic("type has not been registered: " + fqName)
+	}
+	return spec
+}
+
+func setProperties(spec datatype.TypeSpecAccessor, properties ...datatype.TypeSpecPropertyAccessor) {
+	if t, ok := spec.(*datatype.TypeSpec); ok && len(t.DeclaredProperties()) == 0 {
+		t.SetProperties(properties...)
+	}
+}
+`
@@ -0,0 +1,263 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mustLoadPackage(t *testing.T) *model {
+	m, err := loadPackage(filepath.Join("testdata", "package"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestLoadPackage(t *testing.T) {
+	m := mustLoadPackage(t)
+	assert.Equal(t, "hl7.fhir.test.core#4.0.1", m.source)
+
+	names := make([]string, 0)
+	for name := range m.types {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"Element", "BackboneElement", "Extension", "boolean", "string", "code",
+		"decimal", "uri", "dateTime", "Quantity", "Resource", "DomainResource", "Observation",
+		"Observation.component"}, names)
+}
+
+func TestLoadPackageHierarchy(t *testing.T) {
+	m := mustLoadPackage(t)
+	assert.Equal(t, "", m.types["Resource"].base)
+	assert.Equal(t, "Resource", m.types["DomainResource"].base)
+	assert.Equal(t, "DomainResource", m.types["Observation"].base)
+	assert.Equal(t, "BackboneElement", m.types["Observation.component"].base)
+	assert.Equal(t, "string", m.types["code"].base)
+}
+
+func TestLoadPackageProperties(t *testing.T) {
+	m := mustLoadPackage(t)
+	properties := m.types["Observation"].properties
+	if assert.Len(t, properties, 4) {
+		assert.Equal(t, &propertyDefinition{name: "status", types: []string{"code"},
+			min: 1, max: 1, modifier: true, summary: true}, properties[0])
+		assert.Equal(t, &propertyDefinition{name: "value[x]", types: []string{"Quantity", "string", "boolean"},
+			min: 0, max: 1, summary: true}, properties[2])
+		assert.Equal(t, &propertyDefinition{name: "component", types: []string{"Observation.component"},
+			min: 0, max: -1, summary: true}, properties[3])
+	}
+}
+
+func TestLoadPackageBackboneProperties(t *testing.T) {
+	m := mustLoadPackage(t)
+	properties := m.types["Observation.component"].properties
+	if assert.Len(t, properties, 3) {
+		assert.Equal(t, "code", properties[0].name)
+		assert.Equal(t, "value[x]", properties[1].name)
+		assert.Equal(t, &propertyDefinition{name: "component", types: []string{"Observation.component"},
+			min: 0, max: -1}, properties[2])
+	}
+}
+
+func TestLoadPackageSystemTypes(t *testing.T) {
+	m := mustLoadPackage(t)
+	properties := m.types["string"].properties
+	if assert.Len(t, properties, 1) {
+		assert.Equal(t, []string{"System.String"}, properties[0].types)
+	}
+	assert.Empty(t, m.types["code"].properties)
+}
+
+func TestLoadPackageNotFound(t *testing.T) {
+	_, err := loadPackage(filepath.Join("testdata", "missing"))
+	assert.Error(t, err)
+}
+
+func TestLoadPackageInvalidJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "typegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "StructureDefinition-Test.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = loadPackage(dir)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "StructureDefinition-Test.json")
+	}
+}
+
+func TestModelAddDuplicate(t *testing.T) {
+	m := newModel()
+	sd := &structureDefinition{ResourceType: "StructureDefinition", Kind: "resource", Type: "Resource"}
+	assert.NoError(t, m.add(sd))
+	assert.EqualError(t, m.add(sd), "type has already been defined: Resource")
+}
+
+func TestModelAddWithoutType(t *testing.T) {
+	m := newModel()
+	assert.Error(t, m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource"}))
+}
+
+func TestModelAddInvalidMax(t *testing.T) {
+	m := newModel()
+	err := m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource", Type: "Test",
+		Differential: &elementDefinitions{Element: []elementDefinition{
+			{Path: "Test"},
+			{Path: "Test.value", Max: "many", Type: []elementType{{Code: "string"}}},
+		}}})
+	assert.EqualError(t, err, "invalid maximum cardinality of Test.value: many")
+}
+
+func TestModelAddWithoutElementType(t *testing.T) {
+	m := newModel()
+	err := m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource", Type: "Test",
+		Differential: &elementDefinitions{Element: []elementDefinition{
+			{Path: "Test"},
+			{Path: "Test.value", Max: "1"},
+		}}})
+	assert.EqualError(t, err, "element without type: Test.value")
+}
+
+func TestModelAddWithoutParent(t *testing.T) {
+	m := newModel()
+	err := m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource", Type: "Test",
+		Differential: &elementDefinitions{Element: []elementDefinition{
+			{Path: "Test.item.value", Max: "1", Type: []elementType{{Code: "string"}}},
+		}}})
+	assert.EqualError(t, err, "element without parent: Test.item.value")
+}
+
+func TestGenerate(t *testing.T) {
+	source, err := mustLoadPackage(t).generate("model")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "type_specs.go", source, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("model", fset, []*ast.File{file}, nil)
+	assert.NoError(t, err)
+
+	s := string(source)
+	assert.True(t, strings.HasPrefix(s,
+		"// Code generated by typegen from hl7.fhir.test.core#4.0.1; DO NOT EDIT.\n\npackage model\n"))
+	assert.Contains(t, s, "systemStringTypeSpec         = mustLookupTypeSpec(\"System.String\")\n")
+	assert.Contains(t, s, "observationTypeSpec          = typeSpec(\"Observation\", domainResourceTypeSpec)\n")
+	assert.Contains(t, s, "observationComponentTypeSpec = typeSpec(\"Observation.component\", backboneElementTypeSpec)\n")
+	assert.Contains(t, s, "resourceTypeSpec             = typeSpec(\"Resource\", nil)\n")
+	assert.Contains(t, s, "datatype.NewTypeSpecProperty(\"status\", 1, 1, "+
+		"datatype.ModifierPropertyFlag|datatype.SummaryPropertyFlag, codeTypeSpec),\n")
+	assert.Contains(t, s, "datatype.NewTypeSpecProperty(\"value[x]\", 0, 1, datatype.SummaryPropertyFlag, "+
+		"quantityTypeSpec, stringTypeSpec, booleanTypeSpec),\n")
+	assert.Contains(t, s, "datatype.NewTypeSpecProperty(\"component\", 0, datatype.UnboundedCardinality, 0, "+
+		"observationComponentTypeSpec),\n")
+	assert.Contains(t, s, "datatype.NewTypeSpecProperty(\"value\", 0, 1, 0, systemBooleanTypeSpec),\n")
+	assert.NotContains(t, s, "SimpleQuantity")
+	assert.NotContains(t, s, "Model")
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	source1, err := mustLoadPackage(t).generate("model")
+	assert.NoError(t, err)
+	source2, err := mustLoadPackage(t).generate("model")
+	assert.NoError(t, err)
+	assert.Equal(t, string(source1), string(source2))
+}
+
+func TestGenerateUndefinedBase(t *testing.T) {
+	m := newModel()
+	assert.NoError(t, m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource",
+		Type: "Test", BaseDefinition: "http://hl7.org/fhir/StructureDefinition/DomainResource"}))
+	_, err := m.generate("model")
+	assert.EqualError(t, err, "base type DomainResource of Test has not been defined")
+}
+
+func TestGenerateUndefinedPropertyType(t *testing.T) {
+	m := newModel()
+	assert.NoError(t, m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource", Type: "Test",
+		Differential: &elementDefinitions{Element: []elementDefinition{
+			{Path: "Test"},
+			{Path: "Test.value", Max: "1", Type: []elementType{{Code: "string"}}},
+		}}}))
+	_, err := m.generate("model")
+	assert.EqualError(t, err, "type string of Test.value has not been defined")
+}
+
+func TestGenerateIdentifierCollision(t *testing.T) {
+	m := newModel()
+	assert.NoError(t, m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource", Type: "Test"}))
+	assert.NoError(t, m.add(&structureDefinition{ResourceType: "StructureDefinition", Kind: "resource", Type: "test"}))
+	_, err := m.generate("model")
+	assert.EqualError(t, err, "types Test and test result in the same identifier testTypeSpec")
+}
+
+func TestTypeSpecIdentifier(t *testing.T) {
+	assert.Equal(t, "patientTypeSpec", typeSpecIdentifier("Patient"))
+	assert.Equal(t, "dateTimeTypeSpec", typeSpecIdentifier("dateTime"))
+	assert.Equal(t, "patientContactTypeSpec", typeSpecIdentifier("Patient.contact"))
+	assert.Equal(t, "systemStringTypeSpec", typeSpecIdentifier("System.String"))
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "typegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "type_specs.go")
+	if assert.NoError(t, run(filepath.Join("testdata", "package"), "fhir", output)) {
+		source, err := ioutil.ReadFile(output)
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(source), "\npackage fhir\n")
+		}
+	}
+}
+
+func TestRunInvalidPackage(t *testing.T) {
+	assert.Error(t, run(filepath.Join("testdata", "missing"), "fhir", filepath.Join("testdata", "missing.go")))
+}
@@ -28,9 +28,8 @@
 
 package datatype
 
-import "fmt"
-
-var booleanTypeSpec = newElementTypeSpec("boolean")
+var booleanTypeSpec = newElementTypeSpec("boolean").SetProperties(
+	newPrimitiveValueProperty(systemBooleanTypeSpec))
 
 type booleanType struct {
 	PrimitiveType
@@ -66,7 +65,7 @@ func ParseBoolean(value string) (BooleanAccessor, error) {
 	case "false":
 		return NewBoolean(false), nil
 	}
-	return nil, fmt.Errorf("not a boolean: %s", value)
+	return nil, newSyntaxParseError(BooleanDataType, value, commonPrefixLength(value, "true", "false"))
 }
 
 func (t *booleanType) DataType() DataTypes {
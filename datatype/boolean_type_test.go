@@ -128,3 +128,10 @@ func TestBooleanEqualNotEqual(t *testing.T) {
 	assert.Equal(t, false, NewBoolean(false).Equal(NewBoolean(true)))
 	assert.Equal(t, false, NewBoolean(false).Equivalent(NewBoolean(true)))
 }
+
+func TestParseBooleanInvalidPosition(t *testing.T) {
+	_, err := ParseBoolean("fals")
+	assertParseError(t, err, BooleanDataType, 4, InvalidSyntaxParseErrorReason)
+	_, err = ParseBoolean("True")
+	assertParseError(t, err, BooleanDataType, 0, InvalidSyntaxParseErrorReason)
+}
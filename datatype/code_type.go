@@ -29,7 +29,6 @@
 package datatype
 
 import (
-	"fmt"
 	"regexp"
 )
 
@@ -50,19 +49,44 @@ func NewCodeNil() CodeAccessor {
 }
 
 func NewCode(value string) CodeAccessor {
-	if !codeRegexp.MatchString(value) {
-		panic(fmt.Sprintf("not a valid code: %s", value))
+	if pos := codeErrorPosition(value); pos >= 0 {
+		panic(newSyntaxParseError(CodeDataType, value, pos))
 	}
 	return newCode(false, value)
 }
 
 func ParseCode(value string) (CodeAccessor, error) {
-	if !codeRegexp.MatchString(value) {
-		return nil, fmt.Errorf("not a valid code: %s", value)
+	if pos := codeErrorPosition(value); pos >= 0 {
+		return nil, newSyntaxParseError(CodeDataType, value, pos)
 	}
 	return newCode(false, value), nil
 }
 
+func codeErrorPosition(value string) int {
+	if pos := stringErrorPosition(value); pos >= 0 {
+		return pos
+	}
+	if codeRegexp.MatchString(value) {
+		return -1
+	}
+
+	ws := true
+	for pos := 0; pos < len(value); pos++ {
+		if isRegexpSpace(value[pos]) {
+			if ws {
+				return pos
+			}
+			ws = true
+		} else {
+			ws = false
+		}
+	}
+	if ws && len(value) > 0 {
+		return len(value) - 1
+	}
+	return 0
+}
+
 func newCode(nilValue bool, value string) *codeType {
 	return &codeType{
 		stringType{
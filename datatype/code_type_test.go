@@ -85,3 +85,32 @@ func TestCodeEqualEqual(t *testing.T) {
 	assert.Equal(t, true, NewCode("test").Equal(NewString("test")))
 	assert.Equal(t, true, NewCode("test").Equivalent(NewString("test")))
 }
+
+func TestParseCodeInvalidPosition(t *testing.T) {
+	for value, pos := range map[string]int{" Test": 0, "Test  Code": 5, "Test ": 4, "": 0} {
+		_, err := ParseCode(value)
+		assertParseError(t, err, CodeDataType, pos, InvalidSyntaxParseErrorReason)
+	}
+}
+
+func TestCodeInvalidPanicsWithParseError(t *testing.T) {
+	defer func() {
+		assertParseError(t, recover().(error), CodeDataType, 4, InvalidSyntaxParseErrorReason)
+	}()
+	NewCode("Test ")
+}
+
+func TestParseCodeSupplementaryPlane(t *testing.T) {
+	c, err := ParseCode("\U0001F600 code")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "\U0001F600 code", c.String())
+	}
+}
+
+func TestParseCodeInvalidCharacters(t *testing.T) {
+	for value, pos := range map[string]int{"Test\x00Code": 4, "a\xed\xa0\x80": 1, "Test\uFFFF": 4} {
+		_, err := ParseCode(value)
+		assertParseError(t, err, CodeDataType, pos, InvalidSyntaxParseErrorReason)
+		assert.Panics(t, func() { NewCode(value) })
+	}
+}
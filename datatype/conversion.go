@@ -0,0 +1,254 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var integerConversionRegexp = regexp.MustCompile("^[+-]?\\d+$")
+var decimalConversionRegexp = regexp.MustCompile("^[+-]?\\d+(?:\\.\\d+)?$")
+
+var ucumUnityCode = NewCode("1")
+
+func Convert(accessor Accessor, dataType DataTypes) (Accessor, error) {
+	var result Accessor
+	switch dataType {
+	case BooleanDataType:
+		result = toBoolean(accessor)
+	case IntegerDataType:
+		result = toInteger(accessor)
+	case DecimalDataType:
+		result = toDecimal(accessor)
+	case StringDataType:
+		result = toString(accessor)
+	case DateDataType:
+		result = toDate(accessor)
+	case DateTimeDataType:
+		result = toDateTime(accessor)
+	case TimeDataType:
+		result = toTime(accessor)
+	case QuantityDataType:
+		result = toQuantity(accessor)
+	default:
+		return nil, fmt.Errorf("conversion to data type is not supported: %d", dataType)
+	}
+	return result, nil
+}
+
+func ConvertsTo(accessor Accessor, dataType DataTypes) bool {
+	result, err := Convert(accessor, dataType)
+	return err == nil && result != nil
+}
+
+func isStringLike(accessor Accessor) bool {
+	return IsString(accessor) || IsURI(accessor)
+}
+
+func toBoolean(accessor Accessor) BooleanAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == BooleanDataType:
+		return accessor.(BooleanAccessor)
+	case IsNumber(accessor):
+		d := accessor.(NumberAccessor).Decimal()
+		if d.IsZero() {
+			return NewBoolean(false)
+		}
+		if d.Equal(decimalOne) {
+			return NewBoolean(true)
+		}
+	case isStringLike(accessor):
+		switch strings.ToLower(accessor.(PrimitiveAccessor).String()) {
+		case "true", "t", "yes", "y", "1", "1.0":
+			return NewBoolean(true)
+		case "false", "f", "no", "n", "0", "0.0":
+			return NewBoolean(false)
+		}
+	}
+	return nil
+}
+
+func toInteger(accessor Accessor) IntegerAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == IntegerDataType:
+		return accessor.(IntegerAccessor)
+	case IsInteger(accessor):
+		return NewInteger(accessor.(IntegerAccessor).Int())
+	case accessor.DataType() == BooleanDataType:
+		if accessor.(BooleanAccessor).Bool() {
+			return NewInteger(1)
+		}
+		return NewInteger(0)
+	case isStringLike(accessor):
+		value := accessor.(PrimitiveAccessor).String()
+		if integerConversionRegexp.MatchString(value) {
+			if i, err := strconv.ParseInt(value, 10, 32); err == nil {
+				return NewInteger(int32(i))
+			}
+		}
+	}
+	return nil
+}
+
+func toDecimal(accessor Accessor) DecimalAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == DecimalDataType:
+		return accessor.(DecimalAccessor)
+	case IsInteger(accessor):
+		return NewDecimalInt(accessor.(IntegerAccessor).Int())
+	case accessor.DataType() == BooleanDataType:
+		if accessor.(BooleanAccessor).Bool() {
+			return NewDecimal(decimal.New(10, -1))
+		}
+		return NewDecimal(decimal.New(0, -1))
+	case isStringLike(accessor):
+		value := accessor.(PrimitiveAccessor).String()
+		if decimalConversionRegexp.MatchString(value) {
+			if d, err := ParseDecimal(value); err == nil {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+func toString(accessor Accessor) StringAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == StringDataType:
+		return accessor.(StringAccessor)
+	case accessor.DataType() == QuantityDataType:
+		if literal := FormatQuantityLiteral(accessor.(QuantityAccessor)); literal != "" {
+			return NewStringUnchecked(literal)
+		}
+	case IsPrimitive(accessor):
+		if p, ok := accessor.(PrimitiveAccessor); ok {
+			return NewStringUnchecked(p.String())
+		}
+	}
+	return nil
+}
+
+func toDate(accessor Accessor) DateAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == DateDataType:
+		return accessor.(DateAccessor)
+	case accessor.DataType() == DateTimeDataType:
+		return DateFromDateTime(accessor.(DateTimeAccessor))
+	case isStringLike(accessor):
+		value := accessor.(PrimitiveAccessor).String()
+		if d, err := ParseDate(value); err == nil {
+			return d
+		}
+		if dt, err := ParseDateTime(value); err == nil {
+			return DateFromDateTime(dt)
+		}
+	}
+	return nil
+}
+
+func toDateTime(accessor Accessor) DateTimeAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == DateTimeDataType:
+		return accessor.(DateTimeAccessor)
+	case accessor.DataType() == DateDataType:
+		return DateTimeFromDate(accessor.(DateAccessor))
+	case isStringLike(accessor):
+		if dt, err := ParseDateTime(accessor.(PrimitiveAccessor).String()); err == nil {
+			return dt
+		}
+	}
+	return nil
+}
+
+func toTime(accessor Accessor) TimeAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == TimeDataType:
+		return accessor.(TimeAccessor)
+	case isStringLike(accessor):
+		if t, err := ParseTime(accessor.(PrimitiveAccessor).String()); err == nil {
+			return t
+		}
+	}
+	return nil
+}
+
+func toQuantity(accessor Accessor) QuantityAccessor {
+	if Empty(accessor) {
+		return nil
+	}
+
+	switch {
+	case accessor.DataType() == QuantityDataType:
+		return accessor.(QuantityAccessor)
+	case IsNumber(accessor), accessor.DataType() == BooleanDataType:
+		if d := toDecimal(accessor); d != nil {
+			return NewQuantity(d, nil, nil, UCUMSystemURI, ucumUnityCode)
+		}
+	case isStringLike(accessor):
+		if q, err := ParseQuantityLiteral(accessor.(PrimitiveAccessor).String()); err == nil {
+			if q.Code() == nil {
+				return NewQuantity(q.Value(), nil, nil, UCUMSystemURI, ucumUnityCode)
+			}
+			return q
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestConvertUnsupportedDataType(t *testing.T) {
+	r, err := Convert(NewString("1"), URIDataType)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+	assert.False(t, ConvertsTo(NewString("1"), URIDataType))
+}
+
+func TestConvertEmpty(t *testing.T) {
+	for _, dataType := range []DataTypes{BooleanDataType, IntegerDataType, DecimalDataType, StringDataType,
+		DateDataType, DateTimeDataType, TimeDataType, QuantityDataType} {
+		r, err := Convert(nil, dataType)
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, r)
+		r, err = Convert(NewStringNil(), dataType)
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, r)
+		assert.False(t, ConvertsTo(nil, dataType))
+	}
+}
+
+func TestConvertToBoolean(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{NewBoolean(true), true},
+		{NewInteger(1), true},
+		{NewInteger(0), false},
+		{NewInteger(2), nil},
+		{NewDecimalInt(1), true},
+		{NewDecimalFloat64(0.0), false},
+		{NewDecimalFloat64(0.5), nil},
+		{NewString("Yes"), true},
+		{NewString("t"), true},
+		{NewCode("1.0"), true},
+		{NewString("false"), false},
+		{NewString("N"), false},
+		{NewString("0.0"), false},
+		{NewString("maybe"), nil},
+		{NewDate(time.Now()), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, BooleanDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+			assert.False(t, ConvertsTo(test.value, BooleanDataType))
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, test.expected, r.(BooleanAccessor).Bool())
+			assert.True(t, ConvertsTo(test.value, BooleanDataType))
+		}
+	}
+}
+
+func TestConvertToInteger(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{NewInteger(-12), int32(-12)},
+		{NewPositiveInt(12), int32(12)},
+		{NewBoolean(true), int32(1)},
+		{NewBoolean(false), int32(0)},
+		{NewString("+17"), int32(17)},
+		{NewString("-0017"), int32(-17)},
+		{NewString("1.0"), nil},
+		{NewString("2147483648"), nil},
+		{NewDecimalInt(1), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, IntegerDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, IntegerDataType, r.DataType())
+			assert.Equal(t, test.expected, r.(IntegerAccessor).Int())
+		}
+	}
+}
+
+func TestConvertToDecimal(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{mustParseDecimal(t, "1.50"), "1.50"},
+		{NewUnsignedInt(12), "12"},
+		{NewBoolean(true), "1.0"},
+		{NewBoolean(false), "0.0"},
+		{NewString("-3.140"), "-3.140"},
+		{NewString("1e5"), nil},
+		{NewString("1."), nil},
+		{NewTime(time.Now()), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, DecimalDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, DecimalDataType, r.DataType())
+			assert.Equal(t, test.expected, r.(DecimalAccessor).String())
+		}
+	}
+}
+
+func TestConvertToString(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{NewString("test"), "test"},
+		{NewCode("active"), "active"},
+		{NewURI("urn:test"), "urn:test"},
+		{NewInteger(-12), "-12"},
+		{mustParseDecimal(t, "1.50"), "1.50"},
+		{NewBoolean(true), "true"},
+		{NewDateYMDWithPrecision(2020, 3, 1, MonthDatePrecision), "2020-03"},
+		{NewTimeHMSNWithPrecision(10, 20, 0, 0, MinuteTimePrecision), "10:20"},
+		{NewQuantity(NewDecimalInt(4), nil, nil, UCUMSystemURI, NewCode("mg")), "4 'mg'"},
+		{NewQuantity(nil, nil, nil, UCUMSystemURI, NewCode("mg")), nil},
+		{newAccessorMockWithValue(1), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, StringDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, StringDataType, r.DataType())
+			assert.Equal(t, test.expected, r.(StringAccessor).String())
+		}
+	}
+}
+
+func TestConvertToDate(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{NewDateYMD(2020, 3, 1), "2020-03-01"},
+		{NewDateTimeWithPrecision(time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC), HourTimePrecision), "2020-03-01"},
+		{NewDateTimeWithPrecision(time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC), MonthDatePrecision), "2020-03"},
+		{NewString("2020-03"), "2020-03"},
+		{NewString("2020-03-01T10:12:00Z"), "2020-03-01"},
+		{NewString("03/01/2020"), nil},
+		{NewInteger(2020), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, DateDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, DateDataType, r.DataType())
+			assert.Equal(t, test.expected, r.(DateAccessor).String())
+		}
+	}
+}
+
+func TestConvertToDateTime(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{NewDateTimeWithPrecision(time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC), HourTimePrecision), "2020-03-01T10Z"},
+		{NewDateYMDWithPrecision(2020, 3, 1, MonthDatePrecision), "2020-03"},
+		{NewString("2020-03-01T10:12:00Z"), "2020-03-01T10:12:00Z"},
+		{NewString("2020-03-01T10"), nil},
+		{NewTime(time.Now()), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, DateTimeDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, DateTimeDataType, r.DataType())
+			assert.Equal(t, test.expected, r.(DateTimeAccessor).String())
+		}
+	}
+}
+
+func TestConvertToTime(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{NewTimeHMSNWithPrecision(10, 20, 30, 0, SecondTimePrecision), "10:20:30"},
+		{NewString("14:30:00"), "14:30:00"},
+		{NewString("25:00:00"), nil},
+		{NewDateYMD(2020, 3, 1), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, TimeDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, TimeDataType, r.DataType())
+			assert.Equal(t, test.expected, r.(TimeAccessor).String())
+		}
+	}
+}
+
+func TestConvertToQuantity(t *testing.T) {
+	tests := []struct {
+		value    Accessor
+		expected interface{}
+	}{
+		{NewQuantity(NewDecimalInt(4), nil, nil, UCUMSystemURI, NewCode("mg")), "4 'mg'"},
+		{NewInteger(4), "4 '1'"},
+		{mustParseDecimal(t, "4.50"), "4.50 '1'"},
+		{NewBoolean(true), "1.0 '1'"},
+		{NewString("4.5 'mg'"), "4.5 'mg'"},
+		{NewString("3 days"), "3 days"},
+		{NewString("3"), "3 '1'"},
+		{NewString("3 fortnights"), nil},
+		{NewDateYMD(2020, 3, 1), nil},
+	}
+	for _, test := range tests {
+		r, err := Convert(test.value, QuantityDataType)
+		assert.NoError(t, err, "no error expected")
+		if test.expected == nil {
+			assert.Nil(t, r, "no result expected for %v", test.value)
+		} else if assert.NotNil(t, r, "result expected for %v", test.value) {
+			assert.Equal(t, QuantityDataType, r.DataType())
+			assert.Equal(t, test.expected, r.(QuantityAccessor).String())
+		}
+	}
+}
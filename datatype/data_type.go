@@ -136,6 +136,19 @@ func Empty(a Accessor) bool {
 
 var elementTypeSpec = NewTypeSpecWithBase(fqElementTypeName, nil)
 
+var extensionTypeSpec = newElementTypeSpec("Extension").SetProperties(
+	NewTypeSpecProperty("url", 1, 1, 0, uriTypeSpec),
+	NewTypeSpecProperty("value[x]", 0, 1, 0,
+		booleanTypeSpec, codeTypeSpec, dateTypeSpec, dateTimeTypeSpec, decimalTypeSpec, idTypeSpec,
+		integerTypeSpec, markdownTypeSpec, positiveIntTypeSpec, stringTypeSpec, timeTypeSpec,
+		unsignedIntTypeSpec, uriTypeSpec, quantityTypeSpec))
+
+func init() {
+	elementTypeSpec.SetProperties(
+		NewTypeSpecProperty("id", 0, 1, 0, systemStringTypeSpec),
+		NewTypeSpecProperty("extension", 0, UnboundedCardinality, 0, extensionTypeSpec))
+}
+
 func newElementTypeSpec(name string) *TypeSpec {
 	return newElementTypeSpecWithBase(name, elementTypeSpec)
 }
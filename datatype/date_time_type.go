@@ -29,21 +29,17 @@
 package datatype
 
 import (
-	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
 
-var dateTimeTypeSpec = newElementTypeSpec("dateTime")
-
-var timeZoneOffsetRegexp = regexp.MustCompile("^([+-])(\\d{1,2})(?::(\\d{1,2}))$")
-var dateTimeRegexp = regexp.MustCompile("^(\\d(?:\\d(?:\\d[1-9]|[1-9]0)|[1-9]00)|[1-9]000)(?:-(0[1-9]|1[0-2])(?:-(0[1-9]|[1-2]\\d|3[0-1])(?:T([01]\\d|2[0-3]):([0-5]\\d):([0-5]\\d|60)(?:\\.(\\d+))?(Z|[+-](?:(?:0\\d|1[0-3]):[0-5]\\d|14:00)))?)?)?$")
+var dateTimeTypeSpec = newElementTypeSpec("dateTime").SetProperties(
+	newPrimitiveValueProperty(systemDateTimeTypeSpec))
 
 type dateTimeType struct {
 	TemporalType
-	value time.Time
+	value    time.Time
+	timeZone bool
 }
 
 type DateTimeAccessor interface {
@@ -52,17 +48,24 @@ type DateTimeAccessor interface {
 	Minute() int
 	Second() int
 	Nanosecond() int
+	HasTimeZone() bool
+	LowBoundary(precision DateTimePrecisions) DateTimeAccessor
+	HighBoundary(precision DateTimePrecisions) DateTimeAccessor
 }
 
 func NewDateTimeNil() DateTimeAccessor {
-	return newDateTime(true, time.Time{}, NanoTimePrecision)
+	return newDateTime(true, time.Time{}, NanoTimePrecision, false)
 }
 
 func NewDateTime(value time.Time) DateTimeAccessor {
-	return newDateTime(false, value, NanoTimePrecision)
+	return newDateTime(false, value, NanoTimePrecision, true)
 }
 
 func NewDateTimeWithPrecision(value time.Time, precision DateTimePrecisions) DateTimeAccessor {
+	return newDateTimeWithPrecision(value, precision, precision >= HourTimePrecision)
+}
+
+func newDateTimeWithPrecision(value time.Time, precision DateTimePrecisions, timeZone bool) DateTimeAccessor {
 	if precision <= YearDatePrecision {
 		precision = YearDatePrecision
 	} else if precision > NanoTimePrecision {
@@ -92,64 +95,29 @@ func NewDateTimeWithPrecision(value time.Time, precision DateTimePrecisions) Dat
 		nanosecond = 0
 	}
 
-	return newDateTime(false, time.Date(year, month, day, hour, minute, second, nanosecond, value.Location()), precision)
+	return newDateTime(false, time.Date(year, month, day, hour, minute, second, nanosecond, value.Location()),
+		precision, timeZone)
 }
 
 func ParseDateTime(value string) (DateTimeAccessor, error) {
-	parts := dateTimeRegexp.FindStringSubmatch(value)
-	if parts == nil {
-		return nil, fmt.Errorf("not a valid date/time string: %s", value)
-	}
-	return newDateTimeFromParts(parts), nil
+	return DefaultTemporalContext().ParseDateTime(value)
 }
 
-func newDateTimeFromParts(parts []string) DateTimeAccessor {
-	year, _ := strconv.Atoi(parts[1])
-	precision := YearDatePrecision
-
-	month := 1
-	if parts[2] != "" {
-		month, _ = strconv.Atoi(parts[2])
-		precision = MonthDatePrecision
-	}
-
-	day := 1
-	if parts[3] != "" {
-		day, _ = strconv.Atoi(parts[3])
-		precision = DayDatePrecision
-	}
-
-	hour := 0
-	if parts[4] != "" {
-		hour, _ = strconv.Atoi(parts[4])
-		precision = HourTimePrecision
+func parseDateTime(value string, defaultLocation *time.Location) (DateTimeAccessor, error) {
+	var f temporalFields
+	if pos, reason, ok := scanDateTime(value, &f); !ok {
+		return nil, NewParseError(DateTimeDataType, value, pos, reason)
 	}
 
-	minute := 0
-	if parts[5] != "" {
-		minute, _ = strconv.Atoi(parts[5])
-		precision = MinuteTimePrecision
+	location := defaultLocation
+	if f.zone {
+		location = fixedZone(f.offset)
 	}
-
-	second := 0
-	if parts[6] != "" {
-		second, _ = strconv.Atoi(parts[6])
-		precision = SecondTimePrecision
-	}
-
-	nano := 0
-	if parts[7] != "" {
-		nano = parseNanosecond(parts[7])
-		precision = NanoTimePrecision
-	}
-
-	location := mustEvalLocation(parts[8])
-	value := time.Date(year, time.Month(month), day, hour, minute, second, nano, location)
-
-	return newDateTime(false, value, precision)
+	return newDateTime(false, time.Date(f.year, time.Month(f.month), f.day,
+		f.hour, f.minute, f.second, f.nanosecond, location), f.precision, f.zone), nil
 }
 
-func newDateTime(nilValue bool, value time.Time, precision DateTimePrecisions) DateTimeAccessor {
+func newDateTime(nilValue bool, value time.Time, precision DateTimePrecisions, timeZone bool) DateTimeAccessor {
 	return &dateTimeType{
 		TemporalType: TemporalType{
 			PrimitiveType: PrimitiveType{
@@ -157,40 +125,9 @@ func newDateTime(nilValue bool, value time.Time, precision DateTimePrecisions) D
 			},
 			precision: precision,
 		},
-		value: value,
-	}
-}
-
-func mustEvalLocation(value string) *time.Location {
-	if value == "" {
-		return time.Local
-	}
-	if value == "Z" {
-		return time.UTC
-	}
-
-	parts := timeZoneOffsetRegexp.FindStringSubmatch(value)
-	if parts == nil {
-		panic(fmt.Sprintf("not a valid time zone offset: %s", value))
-	}
-
-	hours, _ := strconv.Atoi(parts[2])
-	offset := hours * 60 * 60
-
-	if parts[3] != "" {
-		minutes, _ := strconv.Atoi(parts[3])
-		offset = offset + (minutes * 60)
-	}
-
-	if parts[1] == "-" {
-		offset = -offset
+		value:    value,
+		timeZone: timeZone,
 	}
-
-	if offset == 0 {
-		return time.UTC
-	}
-
-	return time.FixedZone(fmt.Sprint(offset), offset)
 }
 
 func (t *dateTimeType) DataType() DataTypes {
@@ -229,6 +166,18 @@ func (t *dateTimeType) Nanosecond() int {
 	return t.value.Nanosecond()
 }
 
+func (t *dateTimeType) HasTimeZone() bool {
+	return t.timeZone
+}
+
+func (t *dateTimeType) Add(quantity QuantityAccessor) (DateTemporalAccessor, error) {
+	return addDateTime(t, quantity, false)
+}
+
+func (t *dateTimeType) Subtract(quantity QuantityAccessor) (DateTemporalAccessor, error) {
+	return addDateTime(t, quantity, true)
+}
+
 func (t *dateTimeType) TypeSpec() TypeSpecAccessor {
 	return dateTimeTypeSpec
 }
@@ -207,7 +207,7 @@ func TestParseDateTimeNoMonth(t *testing.T) {
 }
 
 func TestMustEvalLocationInvalid(t *testing.T) {
-	assert.Panics(t, func() { mustEvalLocation("X") })
+	assert.Panics(t, func() { mustEvalLocation("X", time.Local) })
 }
 
 func TestDateTimeEqualNil(t *testing.T) {
@@ -404,3 +404,44 @@ func TestNewDateTimeWithPrecisionLessYear(t *testing.T) {
 	assert.Equal(t, 0, v.Nanosecond())
 	assert.Equal(t, YearDatePrecision, v.Precision())
 }
+
+func TestParseDateTimeInvalidPosition(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		reason   ParseErrorReasons
+	}{
+		{"2015-02-07T13", 13, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17", 19, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17.", 20, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17X", 19, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17Zx", 20, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17+1", 21, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17+01-00", 22, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17+01:0", 24, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T13:28:17+14:30", 20, OutOfRangeParseErrorReason},
+		{"2015-02-07T13:28:17+10:60", 23, OutOfRangeParseErrorReason},
+		{"2015-02-07T13:28:17+10:00x", 25, InvalidSyntaxParseErrorReason},
+		{"2015-02-07T24:28:17Z", 11, OutOfRangeParseErrorReason},
+	}
+	for _, test := range tests {
+		_, err := ParseDateTime(test.value)
+		assertParseError(t, err, DateTimeDataType, test.position, test.reason)
+	}
+}
+
+func TestDateTimeHasTimeZone(t *testing.T) {
+	assert.True(t, mustParseDateTime(t, "2015-02-07T13:28:17+02:00").HasTimeZone())
+	assert.False(t, mustParseDateTime(t, "2015-02-07").HasTimeZone())
+	assert.True(t, NewDateTime(time.Now()).HasTimeZone())
+	assert.False(t, NewDateTimeWithPrecision(time.Now(), DayDatePrecision).HasTimeZone())
+	assert.False(t, NewDateTimeNil().HasTimeZone())
+}
+
+func TestDateTimeAddKeepsTimeZoneFlag(t *testing.T) {
+	dt := newDateTimeWithPrecision(time.Date(2014, 5, 17, 8, 10, 20, 0, time.UTC), SecondTimePrecision, false)
+	r, err := dt.Add(mustParseQuantityLiteral(t, "1 day"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.False(t, r.(DateTimeAccessor).HasTimeZone())
+	}
+}
@@ -29,16 +29,12 @@
 package datatype
 
 import (
-	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
 
-var dateTypeSpec = newElementTypeSpec("date")
-
-var dateRegexp = regexp.MustCompile("^(\\d(?:\\d(?:\\d[1-9]|[1-9]0)|[1-9]00)|[1-9]000)(?:-(0[1-9]|1[0-2])(?:-(0[1-9]|[1-2]\\d|3[0-1]))?)?$")
+var dateTypeSpec = newElementTypeSpec("date").SetProperties(
+	newPrimitiveValueProperty(systemDateTypeSpec))
 
 type dateType struct {
 	TemporalType
@@ -52,6 +48,8 @@ type DateAccessor interface {
 	Year() int
 	Month() int
 	Day() int
+	LowBoundary(precision DateTimePrecisions) DateAccessor
+	HighBoundary(precision DateTimePrecisions) DateAccessor
 }
 
 func NewDateNil() DateAccessor {
@@ -63,7 +61,7 @@ func NewDate(value time.Time) DateAccessor {
 }
 
 func NewDateYMD(year int, month int, day int) DateAccessor {
-	return newDate(false, year, month, day, DayDatePrecision)
+	return NewDateYMDWithPrecision(year, month, day, DayDatePrecision)
 }
 
 func NewDateYMDWithPrecision(year int, month int, day int, precision DateTimePrecisions) DateAccessor {
@@ -80,34 +78,34 @@ func NewDateYMDWithPrecision(year int, month int, day int, precision DateTimePre
 		month = 1
 	}
 
+	if err := dateYMDError(year, month, day, precision); err != nil {
+		panic(err)
+	}
 	return newDate(false, year, month, day, precision)
 }
 
 func ParseDate(value string) (DateAccessor, error) {
-	parts := dateRegexp.FindStringSubmatch(value)
-	if parts == nil {
-		return nil, fmt.Errorf("not a valid date string: %s", value)
+	var f temporalFields
+	if pos, reason, ok := scanDate(value, &f); !ok {
+		return nil, NewParseError(DateDataType, value, pos, reason)
 	}
-	return newDateFromParts(parts), nil
-}
-
-func newDateFromParts(parts []string) DateAccessor {
-	year, _ := strconv.Atoi(parts[1])
-	precision := YearDatePrecision
-
-	month := 1
-	if parts[2] != "" {
-		month, _ = strconv.Atoi(parts[2])
-		precision = MonthDatePrecision
-	}
-
-	day := 1
-	if parts[3] != "" {
-		day, _ = strconv.Atoi(parts[3])
-		precision = DayDatePrecision
+	return newDate(false, f.year, f.month, f.day, f.precision), nil
+}
+
+func dateYMDError(year int, month int, day int, precision DateTimePrecisions) *ParseError {
+	var pos int
+	switch {
+	case !validTemporalYear(year):
+		pos = 0
+	case month < 1 || month > 12:
+		pos = 5
+	case day < 1 || day > daysInMonth(year, time.Month(month)):
+		pos = 8
+	default:
+		return nil
 	}
-
-	return newDate(false, year, month, day, precision)
+	return NewParseError(DateDataType, newDate(false, year, month, day, precision).String(),
+		pos, OutOfRangeParseErrorReason)
 }
 
 func newDate(nilValue bool, year int, month int, day int, precision DateTimePrecisions) DateAccessor {
@@ -145,7 +143,15 @@ func (t *dateType) Day() int {
 }
 
 func (t *dateType) Time() time.Time {
-	return time.Date(t.year, time.Month(t.month), t.day, 0, 0, 0, 0, time.Local)
+	return time.Date(t.year, time.Month(t.month), t.day, 0, 0, 0, 0, DefaultTemporalContext().Location())
+}
+
+func (t *dateType) Add(quantity QuantityAccessor) (DateTemporalAccessor, error) {
+	return addDate(t, quantity, false)
+}
+
+func (t *dateType) Subtract(quantity QuantityAccessor) (DateTemporalAccessor, error) {
+	return addDate(t, quantity, true)
 }
 
 func (e *dateType) TypeSpec() TypeSpecAccessor {
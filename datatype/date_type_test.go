@@ -67,6 +67,16 @@ func TestDateNil(t *testing.T) {
 	assert.Equal(t, "", o.String())
 }
 
+func TestDateTimeUsesDefaultLocation(t *testing.T) {
+	location := time.FixedZone("+03:00", 3*60*60)
+	previous := SetDefaultTemporalContext(NewTemporalContext(location, nil))
+	t.Cleanup(func() { SetDefaultTemporalContext(previous) })
+
+	value := NewDateYMD(2020, 1, 2).Time()
+	assert.Same(t, location, value.Location())
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, location), value)
+}
+
 func TestDateValue(t *testing.T) {
 	testTime := time.Now().Add(-time.Hour * 78)
 	o := NewDate(testTime)
@@ -263,3 +273,71 @@ func TestNewDateYMDWithPrecisionInvalid(t *testing.T) {
 	assert.Equal(t, 1, v.Day())
 	assert.Equal(t, YearDatePrecision, v.Precision())
 }
+
+func TestParseDateInvalidPosition(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		reason   ParseErrorReasons
+	}{
+		{"2015-02-0A", 9, InvalidSyntaxParseErrorReason},
+		{"20", 2, InvalidSyntaxParseErrorReason},
+		{"0000", 0, OutOfRangeParseErrorReason},
+		{"2015-13", 5, OutOfRangeParseErrorReason},
+		{"2015-12-32", 8, OutOfRangeParseErrorReason},
+		{"2015/12", 4, InvalidSyntaxParseErrorReason},
+		{"2015-12-01T", 10, InvalidSyntaxParseErrorReason},
+	}
+	for _, test := range tests {
+		_, err := ParseDate(test.value)
+		assertParseError(t, err, DateDataType, test.position, test.reason)
+	}
+}
+
+func TestNewDateYMDInvalidDay(t *testing.T) {
+	assert.Panics(t, func() { NewDateYMD(2021, 2, 29) })
+	assert.Panics(t, func() { NewDateYMD(2021, 4, 31) })
+	assert.Panics(t, func() { NewDateYMD(2021, 1, 0) })
+	assert.NotPanics(t, func() { NewDateYMD(2020, 2, 29) })
+}
+
+func TestNewDateYMDInvalidError(t *testing.T) {
+	tests := []struct {
+		year     int
+		month    int
+		day      int
+		value    string
+		position int
+	}{
+		{0, 1, 1, "0000-01-01", 0},
+		{10000, 1, 1, "10000-01-01", 0},
+		{2021, 13, 1, "2021-13-01", 5},
+		{2021, 2, 31, "2021-02-31", 8},
+	}
+	for _, test := range tests {
+		func() {
+			defer func() {
+				err := recover().(*ParseError)
+				assertParseError(t, err, DateDataType, test.position, OutOfRangeParseErrorReason)
+				assert.Equal(t, test.value, err.Value())
+			}()
+			NewDateYMD(test.year, test.month, test.day)
+		}()
+	}
+}
+
+func TestNewDateYMDWithPrecisionIgnoresDay(t *testing.T) {
+	v := NewDateYMDWithPrecision(2021, 2, 31, MonthDatePrecision)
+	assert.Equal(t, "2021-02", v.String())
+	assert.Panics(t, func() { NewDateYMDWithPrecision(2021, 2, 31, DayDatePrecision) })
+	assert.Panics(t, func() { NewDateYMDWithPrecision(2021, 0, 1, MonthDatePrecision) })
+}
+
+func TestParseDateInvalidCalendarDay(t *testing.T) {
+	for _, value := range []string{"2021-02-29", "2021-02-30", "2021-06-31", "2100-02-29"} {
+		_, err := ParseDate(value)
+		assertParseError(t, err, DateDataType, 8, OutOfRangeParseErrorReason)
+		_, err = ParseDateTime(value + "T10:00:00Z")
+		assertParseError(t, err, DateTimeDataType, 8, OutOfRangeParseErrorReason)
+	}
+}
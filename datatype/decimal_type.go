@@ -29,20 +29,23 @@
 package datatype
 
 import (
-	"fmt"
 	"github.com/shopspring/decimal"
 	"math/big"
 )
 
-var decimalTypeSpec = newElementTypeSpec("decimal")
+var decimalTypeSpec = newElementTypeSpec("decimal").SetProperties(
+	newPrimitiveValueProperty(systemDecimalTypeSpec))
 
 type decimalType struct {
 	PrimitiveType
 	value decimal.Decimal
 }
 
+const maxDecimalPrecision = 28
+
 type DecimalAccessor interface {
 	NumberAccessor
+	Precision() int32
 }
 
 func NewDecimalNil() DecimalAccessor {
@@ -67,12 +70,41 @@ func NewDecimalFloat64(value float64) DecimalAccessor {
 
 func ParseDecimal(value string) (DecimalAccessor, error) {
 	if d, err := decimal.NewFromString(value); err != nil {
-		return nil, fmt.Errorf("not a decimal: %s", value)
+		return nil, newSyntaxParseError(DecimalDataType, value, decimalErrorPosition(value))
 	} else {
 		return newDecimal(false, d), nil
 	}
 }
 
+func decimalErrorPosition(value string) int {
+	pos := 0
+	if len(value) > 0 && (value[0] == '+' || value[0] == '-') {
+		pos++
+	}
+	end := digitsEnd(value, pos)
+	if end == pos {
+		return pos
+	}
+	pos = end
+
+	if pos < len(value) && value[pos] == '.' {
+		end = digitsEnd(value, pos+1)
+		if end == pos+1 {
+			return end
+		}
+		pos = end
+	}
+
+	if pos < len(value) && (value[pos] == 'e' || value[pos] == 'E') {
+		pos++
+		if pos < len(value) && (value[pos] == '+' || value[pos] == '-') {
+			pos++
+		}
+		pos = digitsEnd(value, pos)
+	}
+	return pos
+}
+
 func newDecimal(nilValue bool, value decimal.Decimal) DecimalAccessor {
 	return &decimalType{
 		PrimitiveType: PrimitiveType{
@@ -112,6 +144,10 @@ func (t *decimalType) Decimal() decimal.Decimal {
 	return t.value
 }
 
+func (t *decimalType) Precision() int32 {
+	return decimalScale(t.value)
+}
+
 func (t *decimalType) TypeSpec() TypeSpecAccessor {
 	return decimalTypeSpec
 }
@@ -155,3 +191,33 @@ func (t *decimalType) String() string {
 	}
 	return t.value.StringFixed(-exp)
 }
+
+func DecimalLowBoundary(accessor NumberAccessor, precision int32) DecimalAccessor {
+	if accessor == nil || accessor.Nil() || precision < 0 || precision > maxDecimalPrecision {
+		return nil
+	}
+
+	value := accessor.Decimal()
+	return NewDecimal(decimalFloor(value.Sub(decimalBoundaryDelta(value)), precision))
+}
+
+func DecimalHighBoundary(accessor NumberAccessor, precision int32) DecimalAccessor {
+	if accessor == nil || accessor.Nil() || precision < 0 || precision > maxDecimalPrecision {
+		return nil
+	}
+
+	value := accessor.Decimal()
+	return NewDecimal(decimalCeil(value.Add(decimalBoundaryDelta(value)), precision))
+}
+
+func decimalBoundaryDelta(value decimal.Decimal) decimal.Decimal {
+	return decimal.New(5, -(decimalScale(value) + 1))
+}
+
+func decimalFloor(value decimal.Decimal, precision int32) decimal.Decimal {
+	return value.Shift(precision).Floor().Shift(-precision).Round(precision)
+}
+
+func decimalCeil(value decimal.Decimal, precision int32) decimal.Decimal {
+	return value.Shift(precision).Ceil().Shift(-precision).Round(precision)
+}
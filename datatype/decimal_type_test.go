@@ -209,3 +209,98 @@ func TestDecimalEquivalentRight(t *testing.T) {
 func TestDecimalEquivalentInteger(t *testing.T) {
 	assert.Equal(t, true, NewDecimalFloat64(8274.61).Equivalent(NewInteger(8274)))
 }
+
+func TestDecimalPrecision(t *testing.T) {
+	for value, precision := range map[string]int32{
+		"1": 0, "1.0": 1, "1.00": 2, "-0.050": 3, "100": 0, "1.20e1": 1,
+	} {
+		d, err := ParseDecimal(value)
+		if assert.NoError(t, err, "no error expected for %s", value) {
+			assert.Equal(t, precision, d.Precision(), "unexpected precision for %s", value)
+		}
+	}
+}
+
+func TestDecimalStringTrailingZeros(t *testing.T) {
+	for _, value := range []string{"1.0", "1.00", "-0.050", "0.000000000000000000000000000000"} {
+		d, err := ParseDecimal(value)
+		if assert.NoError(t, err, "no error expected for %s", value) {
+			assert.Equal(t, value, d.String())
+		}
+	}
+}
+
+func TestDecimalLowBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision int32
+		expected  string
+	}{
+		{"1.587", 8, "1.58650000"},
+		{"1.587", 6, "1.586500"},
+		{"1.587", 2, "1.58"},
+		{"1.587", 0, "1"},
+		{"-1.587", 8, "-1.58750000"},
+		{"-1.587", 2, "-1.59"},
+		{"1", 8, "0.50000000"},
+		{"1.0", 1, "0.9"},
+		{"100", 0, "99"},
+	}
+	for _, test := range tests {
+		d, _ := ParseDecimal(test.value)
+		r := DecimalLowBoundary(d, test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s (%d)", test.value, test.precision)
+		}
+	}
+}
+
+func TestDecimalHighBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision int32
+		expected  string
+	}{
+		{"1.587", 8, "1.58750000"},
+		{"1.587", 6, "1.587500"},
+		{"1.587", 2, "1.59"},
+		{"1.587", 0, "2"},
+		{"-1.587", 8, "-1.58650000"},
+		{"-1.587", 2, "-1.58"},
+		{"1", 8, "1.50000000"},
+		{"1.0", 1, "1.1"},
+		{"100", 0, "101"},
+	}
+	for _, test := range tests {
+		d, _ := ParseDecimal(test.value)
+		r := DecimalHighBoundary(d, test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s (%d)", test.value, test.precision)
+		}
+	}
+}
+
+func TestDecimalBoundaryInteger(t *testing.T) {
+	if r := DecimalLowBoundary(NewInteger(5), 1); assert.NotNil(t, r, "result expected") {
+		assert.Equal(t, "4.5", r.String())
+	}
+	if r := DecimalHighBoundary(NewPositiveInt(5), 1); assert.NotNil(t, r, "result expected") {
+		assert.Equal(t, "5.5", r.String())
+	}
+}
+
+func TestDecimalBoundaryEmpty(t *testing.T) {
+	assert.Nil(t, DecimalLowBoundary(nil, 8))
+	assert.Nil(t, DecimalHighBoundary(nil, 8))
+	assert.Nil(t, DecimalLowBoundary(NewDecimalNil(), 8))
+	assert.Nil(t, DecimalHighBoundary(NewDecimalNil(), 8))
+	assert.Nil(t, DecimalLowBoundary(NewDecimalInt(1), -1))
+	assert.Nil(t, DecimalHighBoundary(NewDecimalInt(1), 29))
+}
+
+func TestParseDecimalInvalidPosition(t *testing.T) {
+	for value, pos := range map[string]int{"82737u83": 5, "-": 1, "1.5e": 4, "1e+5x": 4, "": 0} {
+		_, err := ParseDecimal(value)
+		assertParseError(t, err, DecimalDataType, pos, InvalidSyntaxParseErrorReason)
+	}
+}
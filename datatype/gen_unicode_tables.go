@@ -0,0 +1,248 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var unicodeDataFile = flag.String("data", "UnicodeData.txt", "path of UnicodeData.txt")
+var exclusionsFile = flag.String("exclusions", "CompositionExclusions.txt", "path of CompositionExclusions.txt")
+var version = flag.String("version", "", "version of the Unicode character database")
+var outputFile = flag.String("output", "unicode_tables.go", "path of the generated file")
+
+const licenseHeader = `// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+`
+
+var combiningClasses = make(map[rune]uint8)
+var decompositions = make(map[rune][]rune)
+var exclusions = make(map[rune]bool)
+
+func main() {
+	flag.Parse()
+	if *version == "" {
+		log.Fatal("version of the Unicode character database must be specified")
+	}
+
+	readUnicodeData(*unicodeDataFile)
+	readExclusions(*exclusionsFile)
+
+	var b bytes.Buffer
+	b.WriteString(licenseHeader)
+	fmt.Fprintf(&b, "// Code generated by gen_unicode_tables.go from Unicode %s data. DO NOT EDIT.\n\n", *version)
+	fmt.Fprintf(&b, "package datatype\n\n")
+	fmt.Fprintf(&b, "const unicodeTablesVersion = %q\n\n", *version)
+	writeCombiningClasses(&b)
+	writeDecompositions(&b)
+	writeCompositions(&b)
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*outputFile, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readUnicodeData(name string) {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Split(s.Text(), ";")
+		if len(fields) < 6 {
+			continue
+		}
+		r := parseCodePoint(fields[0])
+		ccc, err := strconv.ParseUint(fields[3], 10, 8)
+		if err != nil {
+			log.Fatalf("invalid combining class for %04X: %s", r, fields[3])
+		}
+		if ccc != 0 {
+			combiningClasses[r] = uint8(ccc)
+		}
+		if d := fields[5]; d != "" && !strings.HasPrefix(d, "<") {
+			for _, c := range strings.Fields(d) {
+				decompositions[r] = append(decompositions[r], parseCodePoint(c))
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readExclusions(name string) {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			exclusions[parseCodePoint(line)] = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseCodePoint(value string) rune {
+	r, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		log.Fatalf("invalid code point: %s", value)
+	}
+	return rune(r)
+}
+
+func fullDecomposition(r rune) []rune {
+	d, found := decompositions[r]
+	if !found {
+		return []rune{r}
+	}
+	var result []rune
+	for _, c := range d {
+		result = append(result, fullDecomposition(c)...)
+	}
+	for i := 1; i < len(result); i++ {
+		c := combiningClasses[result[i]]
+		for j := i; c != 0 && j > 0 && combiningClasses[result[j-1]] > c; j-- {
+			result[j-1], result[j] = result[j], result[j-1]
+		}
+	}
+	return result
+}
+
+func sortedRunes(m map[rune][]rune) []rune {
+	runes := make([]rune, 0, len(m))
+	for r := range m {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return runes
+}
+
+func writeCombiningClasses(b *bytes.Buffer) {
+	runes := make([]rune, 0, len(combiningClasses))
+	for r := range combiningClasses {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	fmt.Fprintf(b, "var combiningClassRanges = []combiningClassRange{\n")
+	for i := 0; i < len(runes); {
+		j := i
+		for j+1 < len(runes) && runes[j+1] == runes[j]+1 && combiningClasses[runes[j+1]] == combiningClasses[runes[i]] {
+			j++
+		}
+		fmt.Fprintf(b, "\t{0x%04X, 0x%04X, %d},\n", runes[i], runes[j], combiningClasses[runes[i]])
+		i = j + 1
+	}
+	fmt.Fprintf(b, "}\n\n")
+}
+
+func writeDecompositions(b *bytes.Buffer) {
+	fmt.Fprintf(b, "var canonicalDecompositions = map[rune]string{\n")
+	for _, r := range sortedRunes(decompositions) {
+		fmt.Fprintf(b, "\t0x%04X: \"", r)
+		for _, c := range fullDecomposition(r) {
+			if c > 0xFFFF {
+				fmt.Fprintf(b, "\\U%08X", c)
+			} else {
+				fmt.Fprintf(b, "\\u%04X", c)
+			}
+		}
+		fmt.Fprintf(b, "\",\n")
+	}
+	fmt.Fprintf(b, "}\n\n")
+}
+
+func writeCompositions(b *bytes.Buffer) {
+	fmt.Fprintf(b, "var canonicalCompositions = map[[2]rune]rune{\n")
+	for _, r := range sortedRunes(decompositions) {
+		d := decompositions[r]
+		if len(d) != 2 || exclusions[r] || combiningClasses[r] != 0 || combiningClasses[d[0]] != 0 {
+			continue
+		}
+		fmt.Fprintf(b, "\t{0x%04X, 0x%04X}: 0x%04X,\n", d[0], d[1], r)
+	}
+	fmt.Fprintf(b, "}\n")
+}
@@ -29,7 +29,6 @@
 package datatype
 
 import (
-	"fmt"
 	"regexp"
 )
 
@@ -51,18 +50,29 @@ func NewIDNil() IDAccessor {
 
 func NewID(value string) IDAccessor {
 	if !idRegexp.MatchString(value) {
-		panic(fmt.Sprintf("not a valid ID: %s", value))
+		panic(newSyntaxParseError(IDDataType, value, idErrorPosition(value)))
 	}
 	return newID(false, value)
 }
 
 func ParseID(value string) (IDAccessor, error) {
 	if !idRegexp.MatchString(value) {
-		return nil, fmt.Errorf("not a valid ID: %s", value)
+		return nil, newSyntaxParseError(IDDataType, value, idErrorPosition(value))
 	}
 	return newID(false, value), nil
 }
 
+func idErrorPosition(value string) int {
+	for pos := 0; pos < len(value); pos++ {
+		c := value[pos]
+		if pos == 64 || !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' ||
+			c >= '0' && c <= '9' || c == '-' || c == '.') {
+			return pos
+		}
+	}
+	return 0
+}
+
 func newID(nilValue bool, value string) IDAccessor {
 	return &idType{
 		stringType{
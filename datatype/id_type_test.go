@@ -30,6 +30,7 @@ package datatype
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -86,3 +87,10 @@ func TestIDEqualEqual(t *testing.T) {
 	assert.Equal(t, true, NewID("test").Equal(NewString("test")))
 	assert.Equal(t, true, NewID("test").Equivalent(NewString("test")))
 }
+
+func TestParseIDInvalidPosition(t *testing.T) {
+	for value, pos := range map[string]int{"Test_ID": 4, "": 0, strings.Repeat("a", 65): 64} {
+		_, err := ParseID(value)
+		assertParseError(t, err, IDDataType, pos, InvalidSyntaxParseErrorReason)
+	}
+}
@@ -29,13 +29,13 @@
 package datatype
 
 import (
-	"fmt"
 	"github.com/shopspring/decimal"
 	"math/big"
 	"strconv"
 )
 
-var integerTypeSpec = newElementTypeSpec("integer")
+var integerTypeSpec = newElementTypeSpec("integer").SetProperties(
+	newPrimitiveValueProperty(systemIntegerTypeSpec))
 
 type integerType struct {
 	PrimitiveType
@@ -62,13 +62,53 @@ func NewInteger(value int32) IntegerAccessor {
 }
 
 func ParseInteger(value string) (IntegerAccessor, error) {
-	if i, err := strconv.Atoi(value); err != nil {
-		return nil, fmt.Errorf("not an integer: %s", value)
+	if i, err := strconv.ParseInt(value, 10, 32); err != nil {
+		return nil, integerParseError(IntegerDataType, value, err)
 	} else {
 		return NewInteger(int32(i)), nil
 	}
 }
 
+func ParseIntegerStrict(value string) (IntegerAccessor, error) {
+	if i, err := parseStrictInteger(IntegerDataType, value, true); err != nil {
+		return nil, err
+	} else {
+		return NewInteger(i), nil
+	}
+}
+
+func parseStrictInteger(dataType DataTypes, value string, signed bool) (int32, error) {
+	pos := 0
+	if signed && len(value) > 0 && value[0] == '-' {
+		pos++
+	}
+	end := digitsEnd(value, pos)
+	if end == pos || end < len(value) {
+		return 0, newSyntaxParseError(dataType, value, end)
+	}
+	if value[pos] == '0' && end-pos > 1 {
+		return 0, newSyntaxParseError(dataType, value, pos)
+	}
+
+	i, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, integerParseError(dataType, value, err)
+	}
+	return int32(i), nil
+}
+
+func integerParseError(dataType DataTypes, value string, err error) *ParseError {
+	if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+		return NewParseError(dataType, value, 0, OutOfRangeParseErrorReason)
+	}
+
+	pos := 0
+	if len(value) > 0 && (value[0] == '+' || value[0] == '-') {
+		pos++
+	}
+	return newSyntaxParseError(dataType, value, digitsEnd(value, pos))
+}
+
 func newInteger(nilValue bool, value int32) IntegerAccessor {
 	return &integerType{
 		PrimitiveType: PrimitiveType{
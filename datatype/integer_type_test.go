@@ -31,6 +31,7 @@ package datatype
 import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"math/big"
 	"testing"
 )
@@ -168,3 +169,65 @@ func TestIntegerEquivalent(t *testing.T) {
 	assert.Equal(t, false, NewInteger(8274).Equal(NewDecimalFloat64(8274.8237)))
 	assert.Equal(t, true, NewInteger(8274).Equivalent(NewDecimalFloat64(8274.8237)))
 }
+
+func TestParseIntegerInvalidPosition(t *testing.T) {
+	for value, pos := range map[string]int{"8273.3": 4, "+": 1, "x1": 0, "": 0} {
+		_, err := ParseInteger(value)
+		assertParseError(t, err, IntegerDataType, pos, InvalidSyntaxParseErrorReason)
+	}
+}
+
+func TestParseIntegerOutOfRangeError(t *testing.T) {
+	_, err := ParseInteger("92233720368547758070")
+	assertParseError(t, err, IntegerDataType, 0, OutOfRangeParseErrorReason)
+}
+
+func TestParseIntegerOutOfRange(t *testing.T) {
+	o, err := ParseInteger("2147483648")
+	assert.Nil(t, o, "value unexpected")
+	assertParseError(t, err, IntegerDataType, 0, OutOfRangeParseErrorReason)
+	o, err = ParseInteger("-2147483649")
+	assert.Nil(t, o, "value unexpected")
+	assertParseError(t, err, IntegerDataType, 0, OutOfRangeParseErrorReason)
+}
+
+func TestParseIntegerRangeLimits(t *testing.T) {
+	if o, err := ParseInteger("2147483647"); assert.NoError(t, err) {
+		assert.Equal(t, int32(math.MaxInt32), o.Int())
+	}
+	if o, err := ParseInteger("-2147483648"); assert.NoError(t, err) {
+		assert.Equal(t, int32(math.MinInt32), o.Int())
+	}
+}
+
+func TestParseIntegerStrict(t *testing.T) {
+	for value, expected := range map[string]int32{"0": 0, "-0": 0, "-1": -1, "2147483647": math.MaxInt32, "-2147483648": math.MinInt32} {
+		o, err := ParseIntegerStrict(value)
+		if assert.NoError(t, err, "no error expected for %s", value) {
+			assert.Equal(t, IntegerDataType, o.DataType())
+			assert.Equal(t, expected, o.Int())
+		}
+	}
+}
+
+func TestParseIntegerStrictInvalid(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		reason   ParseErrorReasons
+	}{
+		{"", 0, InvalidSyntaxParseErrorReason},
+		{"+1", 0, InvalidSyntaxParseErrorReason},
+		{"007", 0, InvalidSyntaxParseErrorReason},
+		{"-07", 1, InvalidSyntaxParseErrorReason},
+		{"-00", 1, InvalidSyntaxParseErrorReason},
+		{"-", 1, InvalidSyntaxParseErrorReason},
+		{"12 ", 2, InvalidSyntaxParseErrorReason},
+		{"2147483648", 0, OutOfRangeParseErrorReason},
+	}
+	for _, test := range tests {
+		o, err := ParseIntegerStrict(test.value)
+		assert.Nil(t, o, "value unexpected")
+		assertParseError(t, err, IntegerDataType, test.position, test.reason)
+	}
+}
@@ -0,0 +1,1158 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var markdownThematicBreakRegexp = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+var markdownATXHeadingRegexp = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))??(?:[ \t]+#+)?[ \t]*$`)
+var markdownFenceRegexp = regexp.MustCompile("^(`{3,}|~{3,})(.*)$")
+var markdownSetextRegexp = regexp.MustCompile(`^(?:=+|-+)[ \t]*$`)
+var markdownAutolinkRegexp = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.\-]{1,31}:[^\x00-\x20<>]*)>`)
+var markdownEmailAutolinkRegexp = regexp.MustCompile("^<([A-Za-z0-9.!#$%&'*+/=?^_`{|}~\\-]+@[A-Za-z0-9](?:[A-Za-z0-9\\-]{0,61}[A-Za-z0-9])?(?:\\.[A-Za-z0-9](?:[A-Za-z0-9\\-]{0,61}[A-Za-z0-9])?)*)>")
+var markdownClosingTagRegexp = regexp.MustCompile(`^</[A-Za-z][A-Za-z0-9\-]*[ \t\n\f\r]*>`)
+var markdownEntityRegexp = regexp.MustCompile(`^&(?:#[xX][0-9a-fA-F]{1,6}|#[0-9]{1,7}|[A-Za-z][A-Za-z0-9]{1,31});`)
+var markdownTagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+const markdownMaxNesting = 32
+
+var markdownURLSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"ftp":    true,
+	"mailto": true,
+	"tel":    true,
+}
+
+type markdownListMarker struct {
+	ordered bool
+	char    byte
+	start   int
+	width   int
+}
+
+func (t *markdownType) HTML() string {
+	if t.Nil() {
+		return ""
+	}
+	return renderMarkdownHTML(t.value)
+}
+
+func renderMarkdownHTML(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = expandMarkdownIndent(line)
+	}
+
+	var b strings.Builder
+	b.Grow(len(value) + len(value)/4)
+	renderMarkdownBlocks(&b, lines, false, 0)
+	return b.String()
+}
+
+func expandMarkdownIndent(line string) string {
+	if !strings.HasPrefix(strings.TrimLeft(line, " "), "\t") {
+		return line
+	}
+
+	var b strings.Builder
+	column := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			b.WriteByte(' ')
+			column++
+		case '\t':
+			for n := 4 - column%4; n > 0; n-- {
+				b.WriteByte(' ')
+				column++
+			}
+		default:
+			b.WriteString(line[i:])
+			return b.String()
+		}
+	}
+	return b.String()
+}
+
+func renderMarkdownBlocks(b *strings.Builder, lines []string, tight bool, depth int) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if isMarkdownBlank(line) {
+			i++
+			continue
+		}
+
+		indent := markdownIndent(line)
+		if indent >= 4 {
+			i = renderMarkdownIndentedCode(b, lines, i)
+			continue
+		}
+
+		content := line[indent:]
+		if parts := markdownFenceRegexp.FindStringSubmatch(content); parts != nil &&
+			(parts[1][0] == '~' || !strings.Contains(parts[2], "`")) {
+			i = renderMarkdownFencedCode(b, lines, i, indent, parts[1], parts[2])
+		} else if parts := markdownATXHeadingRegexp.FindStringSubmatch(content); parts != nil {
+			renderMarkdownHeading(b, len(parts[1]), parts[2])
+			i++
+		} else if markdownThematicBreakRegexp.MatchString(content) {
+			b.WriteString("<hr/>")
+			i++
+		} else if content[0] == '>' && depth < markdownMaxNesting {
+			i = renderMarkdownBlockQuote(b, lines, i, depth+1)
+		} else if marker, ok := parseMarkdownListMarker(line); ok && depth < markdownMaxNesting {
+			i = renderMarkdownList(b, lines, i, marker, depth+1)
+		} else {
+			i = renderMarkdownParagraph(b, lines, i, tight)
+		}
+	}
+}
+
+func isMarkdownBlank(line string) bool {
+	return strings.TrimLeft(line, " \t") == ""
+}
+
+func markdownIndent(line string) int {
+	n := 0
+	for n < len(line) && line[n] == ' ' {
+		n++
+	}
+	return n
+}
+
+func startsMarkdownBlock(line string) bool {
+	if markdownIndent(line) >= 4 {
+		return false
+	}
+	content := strings.TrimLeft(line, " ")
+	if content == "" {
+		return false
+	}
+	if marker, ok := parseMarkdownListMarker(line); ok &&
+		!isMarkdownBlank(line[minInt(marker.width, len(line)):]) && (!marker.ordered || marker.start == 1) {
+		return true
+	}
+	return content[0] == '>' ||
+		markdownFenceRegexp.MatchString(content) ||
+		markdownATXHeadingRegexp.MatchString(content) ||
+		markdownThematicBreakRegexp.MatchString(content)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func renderMarkdownIndentedCode(b *strings.Builder, lines []string, i int) int {
+	var code []string
+	end := i
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if isMarkdownBlank(line) {
+			if len(line) > 4 {
+				code = append(code, line[4:])
+			} else {
+				code = append(code, "")
+			}
+			continue
+		}
+		if markdownIndent(line) < 4 {
+			break
+		}
+		code = append(code, line[4:])
+		end = i + 1
+	}
+	code = code[:len(code)-(i-end)]
+
+	b.WriteString("<pre><code>")
+	writeMarkdownEscaped(b, strings.Join(code, "\n")+"\n")
+	b.WriteString("</code></pre>")
+	return end
+}
+
+func renderMarkdownFencedCode(b *strings.Builder, lines []string, i int, indent int, fence string, info string) int {
+	var code []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if markdownIndent(line) < 4 {
+			content := strings.TrimLeft(line, " ")
+			if strings.HasPrefix(content, fence) && strings.Trim(content, fence[:1]+" \t") == "" {
+				i++
+				break
+			}
+		}
+		n := minInt(indent, markdownIndent(line))
+		code = append(code, line[n:])
+	}
+
+	b.WriteString("<pre><code")
+	if fields := strings.Fields(unescapeMarkdown(info)); len(fields) > 0 {
+		b.WriteString(" class=\"language-")
+		writeMarkdownEscaped(b, fields[0])
+		b.WriteByte('"')
+	}
+	b.WriteByte('>')
+	if len(code) > 0 {
+		writeMarkdownEscaped(b, strings.Join(code, "\n")+"\n")
+	}
+	b.WriteString("</code></pre>")
+	return i
+}
+
+func renderMarkdownHeading(b *strings.Builder, level int, text string) {
+	tag := "h" + strconv.Itoa(level)
+	b.WriteByte('<')
+	b.WriteString(tag)
+	b.WriteByte('>')
+	renderMarkdownInline(b, strings.TrimSpace(text))
+	b.WriteString("</")
+	b.WriteString(tag)
+	b.WriteByte('>')
+}
+
+func renderMarkdownBlockQuote(b *strings.Builder, lines []string, i int, depth int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		indent := markdownIndent(line)
+		if content := line[indent:]; indent < 4 && strings.HasPrefix(content, ">") {
+			content = content[1:]
+			if strings.HasPrefix(content, " ") {
+				content = content[1:]
+			}
+			inner = append(inner, content)
+			continue
+		}
+		if isMarkdownBlank(line) || len(inner) == 0 || isMarkdownBlank(inner[len(inner)-1]) ||
+			startsMarkdownBlock(line) {
+			break
+		}
+		inner = append(inner, line)
+	}
+
+	b.WriteString("<blockquote>")
+	renderMarkdownBlocks(b, inner, false, depth)
+	b.WriteString("</blockquote>")
+	return i
+}
+
+func parseMarkdownListMarker(line string) (markdownListMarker, bool) {
+	indent := markdownIndent(line)
+	if indent >= 4 || indent == len(line) {
+		return markdownListMarker{}, false
+	}
+
+	var m markdownListMarker
+	pos := indent
+	switch c := line[pos]; {
+	case c == '-' || c == '+' || c == '*':
+		m.char = c
+		pos++
+	case c >= '0' && c <= '9':
+		digits := 0
+		for pos < len(line) && line[pos] >= '0' && line[pos] <= '9' && digits < 9 {
+			pos++
+			digits++
+		}
+		if pos == len(line) || (line[pos] != '.' && line[pos] != ')') {
+			return m, false
+		}
+		m.ordered, m.char = true, line[pos]
+		m.start, _ = strconv.Atoi(line[indent:pos])
+		pos++
+	default:
+		return m, false
+	}
+
+	spaces := markdownIndent(line[pos:])
+	if pos+spaces < len(line) && spaces == 0 {
+		return m, false
+	}
+	if spaces == 0 || spaces > 4 || pos+spaces == len(line) {
+		spaces = 1
+	}
+	m.width = pos + spaces
+	return m, true
+}
+
+func renderMarkdownList(b *strings.Builder, lines []string, i int, marker markdownListMarker, depth int) int {
+	var items [][]string
+	loose := false
+	for i < len(lines) {
+		m, ok := parseMarkdownListMarker(lines[i])
+		if !ok || m.ordered != marker.ordered || m.char != marker.char ||
+			markdownThematicBreakRegexp.MatchString(strings.TrimLeft(lines[i], " ")) {
+			break
+		}
+
+		first := ""
+		if m.width < len(lines[i]) {
+			first = lines[i][m.width:]
+		}
+		item := []string{first}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if isMarkdownBlank(line) {
+				item = append(item, "")
+				continue
+			}
+			if indent := markdownIndent(line); indent >= m.width {
+				item = append(item, line[m.width:])
+				continue
+			}
+			if _, ok := parseMarkdownListMarker(line); ok || isMarkdownBlank(item[len(item)-1]) ||
+				startsMarkdownBlock(line) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " "))
+		}
+
+		trailing := 0
+		for len(item) > 1 && isMarkdownBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			trailing++
+		}
+		for _, line := range item[1:] {
+			if isMarkdownBlank(line) {
+				loose = true
+			}
+		}
+		if trailing > 0 && i < len(lines) {
+			if next, ok := parseMarkdownListMarker(lines[i]); ok && next.ordered == marker.ordered && next.char == marker.char {
+				loose = true
+			}
+		}
+		items = append(items, item)
+	}
+
+	if marker.ordered {
+		if marker.start != 1 {
+			b.WriteString("<ol start=\"")
+			b.WriteString(strconv.Itoa(marker.start))
+			b.WriteString("\">")
+		} else {
+			b.WriteString("<ol>")
+		}
+	} else {
+		b.WriteString("<ul>")
+	}
+	for _, item := range items {
+		b.WriteString("<li>")
+		renderMarkdownBlocks(b, item, !loose, depth)
+		b.WriteString("</li>")
+	}
+	if marker.ordered {
+		b.WriteString("</ol>")
+	} else {
+		b.WriteString("</ul>")
+	}
+	return i
+}
+
+func renderMarkdownParagraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	var text []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if isMarkdownBlank(line) {
+			break
+		}
+		content := strings.TrimLeft(line, " ")
+		if len(text) > 0 && markdownIndent(line) < 4 {
+			if markdownSetextRegexp.MatchString(content) {
+				level := 1
+				if content[0] == '-' {
+					level = 2
+				}
+				renderMarkdownHeading(b, level, strings.Join(text, "\n"))
+				return i + 1
+			}
+			if startsMarkdownBlock(line) {
+				break
+			}
+		}
+		text = append(text, content)
+	}
+
+	content := strings.TrimRight(strings.Join(text, "\n"), " \t")
+	if !tight {
+		b.WriteString("<p>")
+	}
+	renderMarkdownInline(b, content)
+	if !tight {
+		b.WriteString("</p>")
+	}
+	return i
+}
+
+type markdownInlineNode struct {
+	text      string
+	alt       string
+	delimiter byte
+	length    int
+	count     int
+	canOpen   bool
+	canClose  bool
+	openTags  string
+	closeTags string
+	prev      int
+	next      int
+}
+
+type markdownBracket struct {
+	node   int
+	image  bool
+	active bool
+}
+
+type markdownInlineParser struct {
+	text             string
+	buf              strings.Builder
+	nodes            []markdownInlineNode
+	lastDelimiter    int
+	brackets         []markdownBracket
+	backticks        map[int]int
+	backticksScanned bool
+	indexes          map[string][2]int
+	titles           map[byte][2]int
+}
+
+func renderMarkdownInline(b *strings.Builder, text string) {
+	p := &markdownInlineParser{text: text, lastDelimiter: -1}
+	p.parse()
+	p.processEmphasis(-1)
+	p.render(b, 0, false)
+}
+
+func (p *markdownInlineParser) parse() {
+	text := p.text
+	for i := 0; i < len(text); {
+		switch c := text[i]; c {
+		case '\\':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				p.buf.WriteString("<br/>\n")
+				i += 2
+			} else if i+1 < len(text) && isMarkdownASCIIPunct(text[i+1]) {
+				writeMarkdownEscaped(&p.buf, text[i+1:i+2])
+				i += 2
+			} else {
+				p.buf.WriteByte('\\')
+				i++
+			}
+		case '`':
+			n := markdownRunLength(text, i)
+			if end := p.codeSpanEnd(i+n, n); end >= 0 {
+				p.buf.WriteString("<code>")
+				writeMarkdownEscaped(&p.buf, normalizeMarkdownCodeSpan(text[i+n:end]))
+				p.buf.WriteString("</code>")
+				i = end + n
+			} else {
+				p.buf.WriteString(text[i : i+n])
+				i += n
+			}
+		case '<':
+			i += p.renderAngle(i)
+		case '&':
+			if entity := markdownEntityRegexp.FindString(text[i:]); entity != "" {
+				writeMarkdownEscaped(&p.buf, unescapeMarkdownEntity(entity))
+				i += len(entity)
+			} else {
+				p.buf.WriteString("&amp;")
+				i++
+			}
+		case '!':
+			if i+1 < len(text) && text[i+1] == '[' && len(p.brackets) < markdownMaxNesting {
+				p.pushBracket("![", true)
+				i += 2
+			} else {
+				p.buf.WriteByte(c)
+				i++
+			}
+		case '[':
+			if len(p.brackets) < markdownMaxNesting {
+				p.pushBracket("[", false)
+			} else {
+				p.buf.WriteByte(c)
+			}
+			i++
+		case ']':
+			i = p.closeBracket(i)
+		case '*', '_':
+			n := markdownRunLength(text, i)
+			p.pushDelimiter(i, n)
+			i += n
+		case ' ':
+			n := markdownRunLength(text, i)
+			if i+n < len(text) && text[i+n] == '\n' {
+				if n >= 2 {
+					p.buf.WriteString("<br/>")
+				}
+				p.buf.WriteByte('\n')
+				i += n + 1
+			} else if i+n == len(text) {
+				i += n
+			} else {
+				p.buf.WriteString(text[i : i+n])
+				i += n
+			}
+		default:
+			_, size := utf8.DecodeRuneInString(text[i:])
+			writeMarkdownEscaped(&p.buf, text[i:i+size])
+			i += size
+		}
+	}
+	p.flush()
+}
+
+func (p *markdownInlineParser) flush() {
+	if p.buf.Len() > 0 {
+		p.nodes = append(p.nodes, markdownInlineNode{text: p.buf.String()})
+		p.buf.Reset()
+	}
+}
+
+func (p *markdownInlineParser) render(b *strings.Builder, from int, alt bool) {
+	for _, n := range p.nodes[from:] {
+		if alt && n.alt != "" {
+			b.WriteString(n.alt)
+			continue
+		}
+		if n.delimiter == 0 {
+			b.WriteString(n.text)
+			continue
+		}
+		b.WriteString(n.closeTags)
+		for k := 0; k < n.count; k++ {
+			b.WriteByte(n.delimiter)
+		}
+		b.WriteString(n.openTags)
+	}
+}
+
+func (p *markdownInlineParser) renderAngle(i int) int {
+	text := p.text[i:]
+	if parts := markdownAutolinkRegexp.FindStringSubmatch(text); parts != nil {
+		writeMarkdownLink(&p.buf, parts[1], "", func() { writeMarkdownEscaped(&p.buf, parts[1]) })
+		return len(parts[0])
+	}
+	if parts := markdownEmailAutolinkRegexp.FindStringSubmatch(text); parts != nil {
+		writeMarkdownLink(&p.buf, "mailto:"+parts[1], "", func() { writeMarkdownEscaped(&p.buf, parts[1]) })
+		return len(parts[0])
+	}
+
+	name, end := p.rawHTMLEnd(i)
+	if end < 0 {
+		p.buf.WriteString("&lt;")
+		return 1
+	}
+	if name = strings.ToLower(name); name == "script" || name == "style" {
+		for from := end; ; {
+			closing := p.index("</", from)
+			if closing < 0 {
+				return len(text)
+			}
+			from = closing + 2
+			if len(p.text)-from < len(name) || !strings.EqualFold(p.text[from:from+len(name)], name) {
+				continue
+			}
+			if end := p.index(">", from); end >= 0 {
+				return end + 1 - i
+			}
+			return len(text)
+		}
+	}
+	return end - i
+}
+
+func (p *markdownInlineParser) rawHTMLEnd(i int) (string, int) {
+	text := p.text[i:]
+	switch {
+	case strings.HasPrefix(text, "<!--"):
+		return "", p.indexEnd("-->", i+4)
+	case strings.HasPrefix(text, "<?"):
+		return "", p.indexEnd("?>", i+2)
+	case strings.HasPrefix(text, "<![CDATA["):
+		return "", p.indexEnd("]]>", i+9)
+	case strings.HasPrefix(text, "<!"):
+		if len(text) > 2 && isMarkdownASCIILetter(text[2]) {
+			return "", p.indexEnd(">", i+2)
+		}
+		return "", -1
+	case strings.HasPrefix(text, "</"):
+		if n := len(markdownClosingTagRegexp.FindString(text)); n > 0 {
+			return "", i + n
+		}
+		return "", -1
+	}
+	return p.openTagEnd(i)
+}
+
+func (p *markdownInlineParser) openTagEnd(i int) (string, int) {
+	text := p.text
+	pos := i + 1
+	if pos == len(text) || !isMarkdownASCIILetter(text[pos]) {
+		return "", -1
+	}
+	for pos++; pos < len(text) && (isMarkdownASCIILetter(text[pos]) || isMarkdownDigit(text[pos]) || text[pos] == '-'); pos++ {
+	}
+	name := text[i+1 : pos]
+
+	for {
+		spaces := skipMarkdownHTMLSpaces(text, pos)
+		if spaces < len(text) && text[spaces] == '>' {
+			return name, spaces + 1
+		}
+		if strings.HasPrefix(text[spaces:], "/>") {
+			return name, spaces + 2
+		}
+		if spaces == pos || spaces == len(text) || !isMarkdownHTMLAttributeStart(text[spaces]) {
+			return "", -1
+		}
+		for pos = spaces + 1; pos < len(text) && isMarkdownHTMLAttributeChar(text[pos]); pos++ {
+		}
+
+		value := skipMarkdownHTMLSpaces(text, pos)
+		if value == len(text) || text[value] != '=' {
+			continue
+		}
+		value = skipMarkdownHTMLSpaces(text, value+1)
+		if value == len(text) {
+			return "", -1
+		}
+		if c := text[value]; c == '"' || c == '\'' {
+			end := p.index(text[value:value+1], value+1)
+			if end < 0 {
+				return "", -1
+			}
+			pos = end + 1
+		} else {
+			for pos = value; pos < len(text) && !strings.ContainsRune(" \t\n\f\r\"'=<>`", rune(text[pos])); pos++ {
+			}
+			if pos == value {
+				return "", -1
+			}
+		}
+	}
+}
+
+func (p *markdownInlineParser) index(token string, from int) int {
+	if cached, found := p.indexes[token]; found && from >= cached[0] && (cached[1] < 0 || from <= cached[1]) {
+		return cached[1]
+	}
+
+	end := strings.Index(p.text[from:], token)
+	if end >= 0 {
+		end += from
+	}
+	if p.indexes == nil {
+		p.indexes = make(map[string][2]int)
+	}
+	p.indexes[token] = [2]int{from, end}
+	return end
+}
+
+func (p *markdownInlineParser) indexEnd(token string, from int) int {
+	if end := p.index(token, from); end >= 0 {
+		return end + len(token)
+	}
+	return -1
+}
+
+func (p *markdownInlineParser) codeSpanEnd(from int, n int) int {
+	if p.backticksScanned {
+		if last, found := p.backticks[n]; !found || last < from {
+			return -1
+		}
+	}
+	if p.backticks == nil {
+		p.backticks = make(map[int]int)
+	}
+
+	text := p.text
+	for j := from; j < len(text); {
+		if text[j] != '`' {
+			j++
+			continue
+		}
+		m := markdownRunLength(text, j)
+		p.backticks[m] = j
+		if m == n {
+			return j
+		}
+		j += m
+	}
+	p.backticksScanned = true
+	return -1
+}
+
+func (p *markdownInlineParser) pushBracket(text string, image bool) {
+	p.flush()
+	p.brackets = append(p.brackets, markdownBracket{node: len(p.nodes), image: image, active: true})
+	p.nodes = append(p.nodes, markdownInlineNode{text: text})
+}
+
+func (p *markdownInlineParser) closeBracket(i int) int {
+	if len(p.brackets) == 0 {
+		p.buf.WriteByte(']')
+		return i + 1
+	}
+	bracket := p.brackets[len(p.brackets)-1]
+	p.brackets = p.brackets[:len(p.brackets)-1]
+	if !bracket.active || i+1 >= len(p.text) || p.text[i+1] != '(' {
+		p.buf.WriteByte(']')
+		return i + 1
+	}
+	destination, title, end, ok := p.parseLinkTarget(i + 2)
+	if !ok {
+		p.buf.WriteByte(']')
+		return i + 1
+	}
+
+	p.flush()
+	p.processEmphasis(bracket.node)
+	url, safe := sanitizeMarkdownURL(destination)
+	if bracket.image {
+		var alt strings.Builder
+		p.render(&alt, bracket.node+1, true)
+		plain := markdownTagRegexp.ReplaceAllString(alt.String(), "")
+		p.nodes = p.nodes[:bracket.node+1]
+		if !safe {
+			p.nodes[bracket.node].text = plain
+			return end
+		}
+
+		var img strings.Builder
+		img.WriteString("<img src=\"")
+		writeMarkdownEscaped(&img, url)
+		img.WriteString("\" alt=\"")
+		img.WriteString(plain)
+		if title != "" {
+			img.WriteString("\" title=\"")
+			writeMarkdownEscaped(&img, title)
+		}
+		img.WriteString("\"/>")
+		p.nodes[bracket.node].text = img.String()
+		p.nodes[bracket.node].alt = plain
+		return end
+	}
+
+	for k := range p.brackets {
+		if !p.brackets[k].image {
+			p.brackets[k].active = false
+		}
+	}
+	if !safe {
+		p.nodes[bracket.node].text = ""
+		return end
+	}
+	var a strings.Builder
+	writeMarkdownLinkStart(&a, url, title)
+	p.nodes[bracket.node].text = a.String()
+	p.nodes = append(p.nodes, markdownInlineNode{text: "</a>"})
+	return end
+}
+
+func (p *markdownInlineParser) parseLinkTarget(pos int) (string, string, int, bool) {
+	text := p.text
+	pos = skipMarkdownSpaces(text, pos)
+	if pos >= len(text) {
+		return "", "", 0, false
+	}
+
+	var destination string
+	if text[pos] == '<' {
+		end := strings.IndexAny(text[pos+1:], ">\n")
+		if end < 0 || text[pos+1+end] != '>' {
+			return "", "", 0, false
+		}
+		destination = text[pos+1 : pos+1+end]
+		pos = pos + end + 2
+	} else {
+		start, depth := pos, 0
+		for ; pos < len(text); pos++ {
+			c := text[pos]
+			if c == '\\' && pos+1 < len(text) {
+				pos++
+				continue
+			}
+			if c <= ' ' || (c == ')' && depth == 0) {
+				break
+			}
+			if c == '(' {
+				if depth++; depth > markdownMaxNesting {
+					return "", "", 0, false
+				}
+			} else if c == ')' {
+				depth--
+			}
+		}
+		destination = text[start:pos]
+	}
+
+	title := ""
+	titlePos := skipMarkdownSpaces(text, pos)
+	if titlePos < len(text) && titlePos > pos && (text[titlePos] == '"' || text[titlePos] == '\'' || text[titlePos] == '(') {
+		closing := text[titlePos]
+		if closing == '(' {
+			closing = ')'
+		}
+		end := p.titleEnd(titlePos+1, closing)
+		if end < 0 {
+			return "", "", 0, false
+		}
+		title = unescapeMarkdown(text[titlePos+1 : end])
+		pos = end + 1
+	}
+
+	pos = skipMarkdownSpaces(text, pos)
+	if pos >= len(text) || text[pos] != ')' {
+		return "", "", 0, false
+	}
+	return unescapeMarkdown(destination), title, pos + 1, true
+}
+
+func (p *markdownInlineParser) titleEnd(from int, closing byte) int {
+	if cached, found := p.titles[closing]; found && from >= cached[0] && (cached[1] < 0 || from <= cached[1]) {
+		return cached[1]
+	}
+
+	text := p.text
+	end := from
+	for ; end < len(text) && text[end] != closing; end++ {
+		if text[end] == '\\' {
+			end++
+		}
+	}
+	if end >= len(text) {
+		end = -1
+	}
+	if p.titles == nil {
+		p.titles = make(map[byte][2]int)
+	}
+	p.titles[closing] = [2]int{from, end}
+	return end
+}
+
+func (p *markdownInlineParser) pushDelimiter(i int, n int) {
+	canOpen, canClose := markdownDelimiterFlanking(p.text, i, n)
+	if !canOpen && !canClose {
+		p.buf.WriteString(p.text[i : i+n])
+		return
+	}
+
+	p.flush()
+	p.nodes = append(p.nodes, markdownInlineNode{
+		delimiter: p.text[i],
+		length:    n,
+		count:     n,
+		canOpen:   canOpen,
+		canClose:  canClose,
+		prev:      p.lastDelimiter,
+		next:      -1,
+	})
+	if p.lastDelimiter >= 0 {
+		p.nodes[p.lastDelimiter].next = len(p.nodes) - 1
+	}
+	p.lastDelimiter = len(p.nodes) - 1
+}
+
+func (p *markdownInlineParser) removeDelimiter(i int) {
+	n := &p.nodes[i]
+	if n.prev >= 0 {
+		p.nodes[n.prev].next = n.next
+	}
+	if n.next >= 0 {
+		p.nodes[n.next].prev = n.prev
+	} else {
+		p.lastDelimiter = n.prev
+	}
+}
+
+func (p *markdownInlineParser) processEmphasis(bottom int) {
+	closer := p.lastDelimiter
+	if closer <= bottom {
+		return
+	}
+	for p.nodes[closer].prev > bottom {
+		closer = p.nodes[closer].prev
+	}
+
+	var openersBottom [2][2][3]int
+	for i := range openersBottom {
+		for j := range openersBottom[i] {
+			for k := range openersBottom[i][j] {
+				openersBottom[i][j][k] = bottom
+			}
+		}
+	}
+
+	for closer >= 0 {
+		c := &p.nodes[closer]
+		if !c.canClose {
+			closer = c.next
+			continue
+		}
+
+		openerBottom := &openersBottom[markdownDelimiterIndex(c.delimiter)][markdownBoolIndex(c.canOpen)][c.length%3]
+		opener := c.prev
+		for ; opener > *openerBottom; opener = p.nodes[opener].prev {
+			if o := &p.nodes[opener]; o.canOpen && o.delimiter == c.delimiter &&
+				(!(o.canClose || c.canOpen) || (o.length+c.length)%3 != 0 || (o.length%3 == 0 && c.length%3 == 0)) {
+				break
+			}
+		}
+		if opener <= *openerBottom {
+			*openerBottom = c.prev
+			next := c.next
+			if !c.canOpen {
+				p.removeDelimiter(closer)
+			}
+			closer = next
+			continue
+		}
+
+		o := &p.nodes[opener]
+		use, tag := 1, "em"
+		if o.count >= 2 && c.count >= 2 {
+			use, tag = 2, "strong"
+		}
+		o.count -= use
+		c.count -= use
+		o.openTags = "<" + tag + ">" + o.openTags
+		c.closeTags += "</" + tag + ">"
+
+		o.next, c.prev = closer, opener
+		if o.count == 0 {
+			p.removeDelimiter(opener)
+		}
+		if c.count == 0 {
+			next := c.next
+			p.removeDelimiter(closer)
+			closer = next
+		}
+	}
+
+	for p.lastDelimiter > bottom {
+		p.lastDelimiter = p.nodes[p.lastDelimiter].prev
+	}
+	if p.lastDelimiter >= 0 {
+		p.nodes[p.lastDelimiter].next = -1
+	}
+}
+
+func markdownDelimiterIndex(c byte) int {
+	if c == '_' {
+		return 1
+	}
+	return 0
+}
+
+func markdownBoolIndex(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func writeMarkdownLink(b *strings.Builder, destination string, title string, content func()) {
+	url, ok := sanitizeMarkdownURL(destination)
+	if !ok {
+		content()
+		return
+	}
+
+	writeMarkdownLinkStart(b, url, title)
+	content()
+	b.WriteString("</a>")
+}
+
+func writeMarkdownLinkStart(b *strings.Builder, url string, title string) {
+	b.WriteString("<a href=\"")
+	writeMarkdownEscaped(b, url)
+	if title != "" {
+		b.WriteString("\" title=\"")
+		writeMarkdownEscaped(b, title)
+	}
+	b.WriteString("\">")
+}
+
+func skipMarkdownSpaces(text string, pos int) int {
+	for pos < len(text) && (text[pos] == ' ' || text[pos] == '\t' || text[pos] == '\n') {
+		pos++
+	}
+	return pos
+}
+
+func skipMarkdownHTMLSpaces(text string, pos int) int {
+	for pos < len(text) && strings.IndexByte(" \t\n\f\r", text[pos]) >= 0 {
+		pos++
+	}
+	return pos
+}
+
+func isMarkdownHTMLAttributeStart(c byte) bool {
+	return isMarkdownASCIILetter(c) || c == '_' || c == ':'
+}
+
+func isMarkdownHTMLAttributeChar(c byte) bool {
+	return isMarkdownHTMLAttributeStart(c) || isMarkdownDigit(c) || c == '.' || c == '-'
+}
+
+func markdownDelimiterFlanking(text string, i int, n int) (bool, bool) {
+	prev, next := ' ', ' '
+	if i > 0 {
+		prev, _ = utf8.DecodeLastRuneInString(text[:i])
+	}
+	if i+n < len(text) {
+		next, _ = utf8.DecodeRuneInString(text[i+n:])
+	}
+
+	left := !unicode.IsSpace(next) && (!isMarkdownPunct(next) || unicode.IsSpace(prev) || isMarkdownPunct(prev))
+	right := !unicode.IsSpace(prev) && (!isMarkdownPunct(prev) || unicode.IsSpace(next) || isMarkdownPunct(next))
+	if text[i] == '_' {
+		return left && (!right || isMarkdownPunct(prev)), right && (!left || isMarkdownPunct(next))
+	}
+	return left, right
+}
+
+func markdownRunLength(text string, i int) int {
+	n := 1
+	for i+n < len(text) && text[i+n] == text[i] {
+		n++
+	}
+	return n
+}
+
+func normalizeMarkdownCodeSpan(code string) string {
+	code = strings.ReplaceAll(code, "\n", " ")
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		code = code[1 : len(code)-1]
+	}
+	return code
+}
+
+func unescapeMarkdown(value string) string {
+	if strings.IndexByte(value, '\\') < 0 && strings.IndexByte(value, '&') < 0 {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '\\' && i+1 < len(value) && isMarkdownASCIIPunct(value[i+1]) {
+			b.WriteByte(value[i+1])
+			i++
+		} else if entity := markdownEntityRegexp.FindString(value[i:]); c == '&' && entity != "" {
+			b.WriteString(unescapeMarkdownEntity(entity))
+			i += len(entity) - 1
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func unescapeMarkdownEntity(entity string) string {
+	return strings.Map(func(r rune) rune {
+		if !validStringRune(r, utf8.RuneLen(r)) {
+			return unicode.ReplacementChar
+		}
+		return r
+	}, html.UnescapeString(entity))
+}
+
+func sanitizeMarkdownURL(value string) (string, bool) {
+	clean := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+	if i := strings.IndexAny(clean, ":/?#"); i >= 0 && clean[i] == ':' {
+		if !markdownURLSchemes[strings.ToLower(clean[:i])] {
+			return "", false
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c <= ' ' || c == 0x7f {
+			b.WriteByte('%')
+			b.WriteByte("0123456789ABCDEF"[c>>4])
+			b.WriteByte("0123456789ABCDEF"[c&0xf])
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), true
+}
+
+func isMarkdownASCIIPunct(c byte) bool {
+	return (c >= '!' && c <= '/') || (c >= ':' && c <= '@') || (c >= '[' && c <= '`') || (c >= '{' && c <= '~')
+}
+
+func isMarkdownASCIILetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isMarkdownDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isMarkdownPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func writeMarkdownEscaped(b *strings.Builder, value string) {
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkdownHTMLNil(t *testing.T) {
+	assert.Equal(t, "", NewMarkdownNil().HTML())
+}
+
+func TestMarkdownHTMLEmpty(t *testing.T) {
+	assert.Equal(t, "", NewMarkdown("").HTML())
+}
+
+func TestMarkdownHTMLParagraphs(t *testing.T) {
+	assert.Equal(t, "<p>First\nline</p><p>Second</p>",
+		NewMarkdown("First\nline\n\n\nSecond  ").HTML())
+}
+
+func TestMarkdownHTMLLineEndings(t *testing.T) {
+	assert.Equal(t, "<p>First</p><p>Second\nThird</p>",
+		NewMarkdown("First\r\n\r\nSecond\rThird").HTML())
+}
+
+func TestMarkdownHTMLHardBreak(t *testing.T) {
+	assert.Equal(t, "<p>a<br/>\nb<br/>\nc</p>", NewMarkdown("a  \nb\\\nc").HTML())
+}
+
+func TestMarkdownHTMLHeadings(t *testing.T) {
+	assert.Equal(t, "<h1>Title</h1><h3>Sub</h3><h6>Deep</h6><p>#NoHeading</p>",
+		NewMarkdown("# Title\n### Sub ###\n###### Deep\n#NoHeading").HTML())
+}
+
+func TestMarkdownHTMLSetextHeadings(t *testing.T) {
+	assert.Equal(t, "<h1>Title</h1><h2>Sub\ntitle</h2>",
+		NewMarkdown("Title\n=====\nSub\ntitle\n---").HTML())
+}
+
+func TestMarkdownHTMLThematicBreak(t *testing.T) {
+	assert.Equal(t, "<p>a</p><hr/><p>b</p><hr/>", NewMarkdown("a\n\n***\nb\n\n_ _ _").HTML())
+}
+
+func TestMarkdownHTMLEmphasis(t *testing.T) {
+	assert.Equal(t, "<p><em>a</em> <em>b</em> <strong>c</strong> <strong>d</strong> <em><strong>e</strong></em></p>",
+		NewMarkdown("*a* _b_ **c** __d__ ***e***").HTML())
+}
+
+func TestMarkdownHTMLNestedEmphasis(t *testing.T) {
+	assert.Equal(t, "<p><em>a <strong>b</strong> c</em> <strong>d <em>e</em></strong></p>",
+		NewMarkdown("*a **b** c* **d *e***").HTML())
+}
+
+func TestMarkdownHTMLIntrawordUnderscore(t *testing.T) {
+	assert.Equal(t, "<p>snake_case_name and a*b*c</p>",
+		NewMarkdown("snake_case_name and a\\*b\\*c").HTML())
+}
+
+func TestMarkdownHTMLUnmatchedEmphasis(t *testing.T) {
+	assert.Equal(t, "<p>*a * b **c</p>", NewMarkdown("*a * b **c").HTML())
+}
+
+func TestMarkdownHTMLCodeSpan(t *testing.T) {
+	assert.Equal(t, "<p><code>a &lt;b&gt; *c*</code> and <code>x ` y</code> and `open</p>",
+		NewMarkdown("`a <b> *c*` and `` x ` y `` and `open").HTML())
+}
+
+func TestMarkdownHTMLFencedCode(t *testing.T) {
+	assert.Equal(t, "<pre><code class=\"language-json\">{\n  &quot;a&quot;: &quot;&lt;b&gt;&quot;\n}\n</code></pre><p>after</p>",
+		NewMarkdown("```json\n{\n  \"a\": \"<b>\"\n}\n```\nafter").HTML())
+}
+
+func TestMarkdownHTMLFencedCodeUnclosed(t *testing.T) {
+	assert.Equal(t, "<pre><code>a\n\nb\n</code></pre>", NewMarkdown("~~~\na\n\nb").HTML())
+}
+
+func TestMarkdownHTMLIndentedCode(t *testing.T) {
+	assert.Equal(t, "<pre><code>a\n\n  b\n</code></pre><p>c</p>",
+		NewMarkdown("    a\n\n      b\n\nc").HTML())
+}
+
+func TestMarkdownHTMLBlockQuote(t *testing.T) {
+	assert.Equal(t, "<blockquote><h2>Note</h2><p>quoted\nlazy</p></blockquote><p>after</p>",
+		NewMarkdown("> ## Note\n> quoted\nlazy\n\nafter").HTML())
+}
+
+func TestMarkdownHTMLTightList(t *testing.T) {
+	assert.Equal(t, "<ul><li>one</li><li>two<ul><li>nested</li></ul></li><li>three</li></ul>",
+		NewMarkdown("- one\n- two\n  - nested\n- three").HTML())
+}
+
+func TestMarkdownHTMLLooseList(t *testing.T) {
+	assert.Equal(t, "<ul><li><p>one</p><p>more</p></li><li><p>two</p></li></ul>",
+		NewMarkdown("* one\n\n  more\n* two").HTML())
+}
+
+func TestMarkdownHTMLOrderedList(t *testing.T) {
+	assert.Equal(t, "<ol><li>a</li><li>b</li></ol><ol start=\"3\"><li>c</li></ol>",
+		NewMarkdown("1. a\n2. b\n\n\n3) c").HTML())
+}
+
+func TestMarkdownHTMLListAfterParagraph(t *testing.T) {
+	assert.Equal(t, "<p>Items:</p><ul><li>a</li></ul><p>Count\n2. b</p>",
+		NewMarkdown("Items:\n+ a\n\nCount\n2. b").HTML())
+}
+
+func TestMarkdownHTMLLinks(t *testing.T) {
+	assert.Equal(t, "<p><a href=\"http://hl7.org/fhir\" title=\"FHIR &quot;R4&quot;\">the <em>spec</em></a>, "+
+		"<a href=\"Patient/1#name\">patient</a>, <a href=\"a%20b(c)\">x</a></p>",
+		NewMarkdown("[the *spec*](http://hl7.org/fhir \"FHIR \\\"R4\\\"\"), "+
+			"[patient](Patient/1#name), [x](<a b(c)>)").HTML())
+}
+
+func TestMarkdownHTMLNestedLinks(t *testing.T) {
+	assert.Equal(t, "<p>[a <a href=\"c\">b</a> d](e)</p>", NewMarkdown("[a [b](c) d](e)").HTML())
+}
+
+func TestMarkdownHTMLEmphasisInLink(t *testing.T) {
+	assert.Equal(t, "<p>**a <a href=\"c\">b**</a></p>", NewMarkdown("**a [b**](c)").HTML())
+	assert.Equal(t, "<p><a href=\"e\">*d</a>*</p>", NewMarkdown("[*d](e)*").HTML())
+}
+
+func TestMarkdownHTMLAutolinks(t *testing.T) {
+	assert.Equal(t, "<p><a href=\"https://hl7.org/?a=1&amp;b=2\">https://hl7.org/?a=1&amp;b=2</a> "+
+		"<a href=\"mailto:info@example.com\">info@example.com</a></p>",
+		NewMarkdown("<https://hl7.org/?a=1&b=2> <info@example.com>").HTML())
+}
+
+func TestMarkdownHTMLImage(t *testing.T) {
+	assert.Equal(t, "<p><img src=\"img/logo.png\" alt=\"the logo\" title=\"Logo\"/></p>",
+		NewMarkdown("![the *logo*](img/logo.png 'Logo')").HTML())
+}
+
+func TestMarkdownHTMLNestedImage(t *testing.T) {
+	assert.Equal(t, "<p><img src=\"d\" alt=\"a b\"/></p>", NewMarkdown("![a ![b](c)](d)").HTML())
+}
+
+func TestMarkdownHTMLUnsafeLinks(t *testing.T) {
+	assert.Equal(t, "<p>a b c d e f</p>",
+		NewMarkdown("[a](javascript:alert(1)) [b](JavaScript:alert(1)) [c](java\\\nscript:x) "+
+			"[d](&#106;avascript:x) [e](vbscript:x) [f](data:text/html;base64,PHNjcmlwdD4=)").HTML())
+}
+
+func TestMarkdownHTMLUnsafeAutolink(t *testing.T) {
+	assert.Equal(t, "<p>javascript:alert(1)</p>", NewMarkdown("<javascript:alert(1)>").HTML())
+}
+
+func TestMarkdownHTMLUnsafeImage(t *testing.T) {
+	assert.Equal(t, "<p>x</p>", NewMarkdown("![x](javascript:alert(1))").HTML())
+}
+
+func TestMarkdownHTMLUnresolvedLink(t *testing.T) {
+	assert.Equal(t, "<p>[a] [b](c and [d]</p>", NewMarkdown("[a] [b](c and [d]").HTML())
+}
+
+func TestMarkdownHTMLRawHTML(t *testing.T) {
+	assert.Equal(t, "<p>a bold text link</p>",
+		NewMarkdown("a <b class=\"x\">bold</b> <!-- comment -->text <a href=\"javascript:x\" onclick='y'>link</a>").HTML())
+}
+
+func TestMarkdownHTMLRawHTMLAttributes(t *testing.T) {
+	assert.Equal(t, "<p>a b c</p>", NewMarkdown("a <x y='1>2' z=w v/>b<!DOCTYPE html> <![CDATA[<i>]]>c").HTML())
+}
+
+func TestMarkdownHTMLScript(t *testing.T) {
+	assert.Equal(t, "<p>before  after</p><p>x</p>",
+		NewMarkdown("before <script>alert('<b>')</script> after\n\nx<SCRIPT>alert(1)").HTML())
+}
+
+func TestMarkdownHTMLEscaping(t *testing.T) {
+	assert.Equal(t, "<p>1 &lt; 2 &gt; 0 &amp; &quot;x&quot; &amp;unknown; \u00a9 \u00e9 *a* \\w</p>",
+		NewMarkdown("1 < 2 > 0 & \"x\" &unknown; &copy; &#xe9; \\*a\\* \\w").HTML())
+}
+
+func TestMarkdownHTMLInvalidCharacterReference(t *testing.T) {
+	assert.Equal(t, "<p>\ufffd\ufffd</p>", NewMarkdown("&#1;&#0;").HTML())
+}
+
+func TestMarkdownHTMLTabs(t *testing.T) {
+	assert.Equal(t, "<pre><code>code\ta\n</code></pre>", NewMarkdown("\tcode\ta").HTML())
+}
+
+func TestMarkdownHTMLNestingLimit(t *testing.T) {
+	result := NewMarkdown(strings.Repeat("> ", markdownMaxNesting+8) + "x").HTML()
+	assert.Equal(t, markdownMaxNesting, strings.Count(result, "<blockquote>"))
+	assert.Contains(t, result, "<p>"+strings.Repeat("&gt; ", 7)+"&gt; x</p>")
+
+	result = NewMarkdown(strings.Repeat("- ", markdownMaxNesting+8) + "x").HTML()
+	assert.Equal(t, markdownMaxNesting, strings.Count(result, "<ul>"))
+}
+
+func TestMarkdownHTMLPathologicalInput(t *testing.T) {
+	inputs := []string{
+		strings.Repeat("**a *b ", 10000),
+		strings.Repeat("*a _b ", 10000) + strings.Repeat("_* ", 10000),
+		strings.Repeat("- ", 10000) + "x",
+		strings.Repeat("[", 10000) + strings.Repeat("](a\\(", 10000),
+		strings.Repeat("![", 10000) + strings.Repeat("](u)", 10000),
+		strings.Repeat("[a](b (", 10000),
+		strings.Repeat("`` ` ", 10000),
+		strings.Repeat("<!-- <? <![CDATA[ <a x=\"", 10000),
+		strings.Repeat("<script></script>", 10000),
+	}
+	for _, input := range inputs {
+		start := time.Now()
+		NewMarkdown(input).HTML()
+		assert.Less(t, int64(time.Since(start)), int64(time.Second), "rendering took too long for %.20q", input)
+	}
+}
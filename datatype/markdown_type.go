@@ -36,6 +36,7 @@ type markdownType struct {
 
 type MarkdownAccessor interface {
 	StringAccessor
+	HTML() string
 }
 
 func NewMarkdownNil() MarkdownAccessor {
@@ -43,9 +44,19 @@ func NewMarkdownNil() MarkdownAccessor {
 }
 
 func NewMarkdown(value string) MarkdownAccessor {
+	if pos := stringErrorPosition(value); pos >= 0 {
+		panic(newSyntaxParseError(MarkdownDataType, value, pos))
+	}
 	return newMarkdown(false, value)
 }
 
+func ParseMarkdown(value string) (MarkdownAccessor, error) {
+	if pos := stringErrorPosition(value); pos >= 0 {
+		return nil, newSyntaxParseError(MarkdownDataType, value, pos)
+	}
+	return newMarkdown(false, value), nil
+}
+
 func newMarkdown(nilValue bool, value string) MarkdownAccessor {
 	return &markdownType{
 		stringType{
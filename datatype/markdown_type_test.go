@@ -67,3 +67,22 @@ func TestMarkdownEqualEqual(t *testing.T) {
 	assert.Equal(t, true, NewMarkdown("test").Equal(NewString("test")))
 	assert.Equal(t, true, NewMarkdown("test").Equivalent(NewString("test")))
 }
+
+func TestParseMarkdown(t *testing.T) {
+	o, err := ParseMarkdown("# Title \U0001F600\n\nText")
+	if assert.NoError(t, err) && assert.NotNil(t, o) {
+		assert.Equal(t, MarkdownDataType, o.DataType())
+		assert.Equal(t, "# Title \U0001F600\n\nText", o.String())
+	}
+}
+
+func TestParseMarkdownInvalid(t *testing.T) {
+	_, err := ParseMarkdown("# Title\x00")
+	assertParseError(t, err, MarkdownDataType, 7, InvalidSyntaxParseErrorReason)
+	_, err = ParseMarkdown("# \xed\xa0\x80")
+	assertParseError(t, err, MarkdownDataType, 2, InvalidSyntaxParseErrorReason)
+}
+
+func TestMarkdownInvalid(t *testing.T) {
+	assert.Panics(t, func() { NewMarkdown("Test\uFFFE") })
+}
@@ -30,12 +30,9 @@ package datatype
 
 import (
 	"github.com/shopspring/decimal"
-	"math"
 	"math/big"
 )
 
-var decimalTen = decimal.NewFromInt32(10)
-
 type NumberAccessor interface {
 	PrimitiveAccessor
 	Int() int32
@@ -67,19 +64,12 @@ func leastPrecisionDecimal(d1 decimal.Decimal, d2 decimal.Decimal) (decimal.Deci
 }
 
 func decimalPrecision(d decimal.Decimal) int32 {
-	precision := -d.Exponent()
-	if precision <= 0 {
-		return 0
-	}
+	return decimalScale(trimDecimalScale(d, 0))
+}
 
-	v := d.Mul(decimal.NewFromInt(int64(math.Pow(10.0, float64(precision)))))
-	for precision > 0 {
-		m := v.Mod(decimalTen)
-		if !m.IsZero() {
-			return precision
-		}
-		v = v.Div(decimalTen)
-		precision = precision - 1
+func CompareNumbers(a NumberAccessor, b NumberAccessor) ComparisonResults {
+	if a == nil || b == nil || a.Nil() || b.Nil() {
+		return EmptyComparisonResult
 	}
-	return precision
+	return comparisonResult(a.Decimal().Cmp(b.Decimal()))
 }
@@ -0,0 +1,174 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"math"
+	"math/big"
+)
+
+type ArithmeticOperators int
+
+const (
+	AdditionOperator ArithmeticOperators = iota
+	SubtractionOperator
+	MultiplicationOperator
+	DivisionOperator
+	DivOperator
+	ModOperator
+)
+
+const divisionPrecision = 8
+
+var maxDecimal = decimal.New(1, 20)
+var bigTen = big.NewInt(10)
+
+func Calculate(left NumberAccessor, operator ArithmeticOperators, right NumberAccessor) (NumberAccessor, error) {
+	if left == nil || right == nil || left.Nil() || right.Nil() {
+		return nil, nil
+	}
+
+	if IsInteger(left) && IsInteger(right) {
+		return calculateInteger(left.Int64(), operator, right.Int64())
+	}
+	return calculateDecimal(left.Decimal(), operator, right.Decimal())
+}
+
+func calculateInteger(left int64, operator ArithmeticOperators, right int64) (NumberAccessor, error) {
+	var result int64
+	switch operator {
+	case AdditionOperator:
+		result = left + right
+	case SubtractionOperator:
+		result = left - right
+	case MultiplicationOperator:
+		result = left * right
+	case DivisionOperator:
+		return calculateDecimal(decimal.NewFromInt(left), operator, decimal.NewFromInt(right))
+	case DivOperator:
+		if right == 0 {
+			return nil, nil
+		}
+		result = left / right
+	case ModOperator:
+		if right == 0 {
+			return nil, nil
+		}
+		result = left % right
+	default:
+		return nil, fmt.Errorf("not a valid arithmetic operator: %d", operator)
+	}
+
+	return newIntegerResult(result), nil
+}
+
+func calculateDecimal(left decimal.Decimal, operator ArithmeticOperators, right decimal.Decimal) (NumberAccessor, error) {
+	var result decimal.Decimal
+	switch operator {
+	case AdditionOperator:
+		result = left.Add(right)
+	case SubtractionOperator:
+		result = left.Sub(right)
+	case MultiplicationOperator:
+		result = left.Mul(right)
+	case DivisionOperator:
+		if right.IsZero() {
+			return nil, nil
+		}
+		result = trimDecimalScale(left.DivRound(right, divisionPrecision),
+			maxInt32(decimalScale(left), decimalScale(right)))
+	case DivOperator:
+		if right.IsZero() {
+			return nil, nil
+		}
+		q, _ := left.QuoRem(right, 0)
+		if !q.BigInt().IsInt64() {
+			return nil, nil
+		}
+		return newIntegerResult(q.IntPart()), nil
+	case ModOperator:
+		if right.IsZero() {
+			return nil, nil
+		}
+		result = left.Mod(right)
+	default:
+		return nil, fmt.Errorf("not a valid arithmetic operator: %d", operator)
+	}
+
+	return newDecimalResult(result), nil
+}
+
+func newIntegerResult(value int64) NumberAccessor {
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return nil
+	}
+	return NewInteger(int32(value))
+}
+
+func newDecimalResult(value decimal.Decimal) NumberAccessor {
+	if value.Abs().GreaterThanOrEqual(maxDecimal) {
+		return nil
+	}
+	return NewDecimal(value)
+}
+
+func decimalScale(d decimal.Decimal) int32 {
+	if exp := d.Exponent(); exp < 0 {
+		return -exp
+	}
+	return 0
+}
+
+func trimDecimalScale(d decimal.Decimal, minScale int32) decimal.Decimal {
+	exp := d.Exponent()
+	if -exp <= minScale {
+		return d
+	}
+
+	value := d.Coefficient()
+	q, r := new(big.Int), new(big.Int)
+	for -exp > minScale {
+		q.QuoRem(value, bigTen, r)
+		if r.Sign() != 0 {
+			break
+		}
+		value.Set(q)
+		exp++
+	}
+	return decimal.NewFromBigInt(value, exp)
+}
+
+func maxInt32(v1 int32, v2 int32) int32 {
+	if v1 > v2 {
+		return v1
+	}
+	return v2
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestCalculateNil(t *testing.T) {
+	r, err := Calculate(nil, AdditionOperator, NewInteger(1))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r, "empty result expected")
+	r, err = Calculate(NewInteger(1), AdditionOperator, NewIntegerNil())
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r, "empty result expected")
+}
+
+func TestCalculateInvalidOperator(t *testing.T) {
+	r, err := Calculate(NewInteger(1), ArithmeticOperators(-1), NewInteger(2))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r, "no result expected")
+	r, err = Calculate(NewDecimalInt(1), ArithmeticOperators(-1), NewInteger(2))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r, "no result expected")
+}
+
+func TestCalculateInteger(t *testing.T) {
+	tests := []struct {
+		operator ArithmeticOperators
+		left     int32
+		right    int32
+		expected int32
+	}{
+		{AdditionOperator, 7, 5, 12},
+		{SubtractionOperator, 7, 9, -2},
+		{MultiplicationOperator, -7, 5, -35},
+		{DivOperator, 5, 2, 2},
+		{DivOperator, -5, 2, -2},
+		{ModOperator, 5, 2, 1},
+		{ModOperator, -5, 2, -1},
+	}
+	for _, test := range tests {
+		r, err := Calculate(NewInteger(test.left), test.operator, NewInteger(test.right))
+		assert.NoError(t, err, "no error expected")
+		if assert.NotNil(t, r, "result expected") {
+			assert.Equal(t, IntegerDataType, r.DataType())
+			assert.Equal(t, test.expected, r.Int())
+		}
+	}
+}
+
+func TestCalculateIntegerSubtypes(t *testing.T) {
+	r, err := Calculate(NewPositiveInt(7), SubtractionOperator, NewUnsignedInt(9))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, r, "result expected") {
+		assert.Equal(t, IntegerDataType, r.DataType())
+		assert.Equal(t, int32(-2), r.Int())
+	}
+}
+
+func TestCalculateIntegerOverflow(t *testing.T) {
+	for _, operator := range []ArithmeticOperators{AdditionOperator, MultiplicationOperator} {
+		r, err := Calculate(NewInteger(math.MaxInt32), operator, NewInteger(2))
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, r, "empty result expected")
+	}
+	r, err := Calculate(NewInteger(math.MinInt32), SubtractionOperator, NewInteger(1))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r, "empty result expected")
+	r, err = Calculate(NewInteger(math.MinInt32), DivOperator, NewInteger(-1))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r, "empty result expected")
+}
+
+func TestCalculateIntegerDivision(t *testing.T) {
+	r, err := Calculate(NewInteger(1), DivisionOperator, NewInteger(3))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, r, "result expected") {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "0.33333333", r.String())
+	}
+	r, err = Calculate(NewInteger(10), DivisionOperator, NewInteger(4))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, r, "result expected") {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "2.5", r.String())
+	}
+}
+
+func TestCalculateDivisionByZero(t *testing.T) {
+	for _, operator := range []ArithmeticOperators{DivisionOperator, DivOperator, ModOperator} {
+		r, err := Calculate(NewInteger(5), operator, NewInteger(0))
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, r, "empty result expected")
+		r, err = Calculate(NewDecimalFloat64(5.5), operator, NewDecimalInt(0))
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, r, "empty result expected")
+	}
+}
+
+func TestCalculateDecimal(t *testing.T) {
+	tests := []struct {
+		operator ArithmeticOperators
+		left     string
+		right    string
+		expected string
+	}{
+		{AdditionOperator, "1.10", "2", "3.10"},
+		{SubtractionOperator, "0.3", "0.1", "0.2"},
+		{MultiplicationOperator, "1.5", "1.5", "2.25"},
+		{DivisionOperator, "2.00", "4", "0.50"},
+		{DivisionOperator, "2", "3.0", "0.66666667"},
+		{ModOperator, "5.5", "0.7", "0.6"},
+		{ModOperator, "-5.5", "0.7", "-0.6"},
+	}
+	for _, test := range tests {
+		left, _ := ParseDecimal(test.left)
+		right, _ := ParseDecimal(test.right)
+		r, err := Calculate(left, test.operator, right)
+		assert.NoError(t, err, "no error expected")
+		if assert.NotNil(t, r, "result expected") {
+			assert.Equal(t, DecimalDataType, r.DataType())
+			assert.Equal(t, test.expected, r.String())
+		}
+	}
+}
+
+func TestCalculateDecimalDiv(t *testing.T) {
+	r, err := Calculate(NewDecimalFloat64(5.5), DivOperator, NewDecimalFloat64(0.7))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, r, "result expected") {
+		assert.Equal(t, IntegerDataType, r.DataType())
+		assert.Equal(t, int32(7), r.Int())
+	}
+}
+
+func TestCalculateDecimalDivOverflow(t *testing.T) {
+	r, err := Calculate(NewDecimal(decimal.New(1, 19)), DivOperator, NewDecimalFloat64(0.1))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r, "empty result expected")
+}
+
+func TestCalculateMixed(t *testing.T) {
+	r, err := Calculate(NewInteger(2), AdditionOperator, NewDecimalFloat64(0.5))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, r, "result expected") {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "2.5", r.String())
+	}
+}
+
+func TestCalculateDecimalOverflow(t *testing.T) {
+	r, err := Calculate(NewDecimal(decimal.New(1, 19)), MultiplicationOperator, NewInteger(10))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r, "empty result expected")
+}
+
+func TestTrimDecimalScale(t *testing.T) {
+	d, _ := decimal.NewFromString("1.2300000")
+	assert.Equal(t, "1.23", trimDecimalScale(d, 0).String())
+	assert.Equal(t, "1.2300", trimDecimalScale(d, 4).StringFixed(4))
+	assert.Equal(t, int32(-4), trimDecimalScale(d, 4).Exponent())
+	assert.Equal(t, int32(-7), trimDecimalScale(d, 8).Exponent())
+}
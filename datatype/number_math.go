@@ -0,0 +1,210 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"math"
+	"math/big"
+)
+
+const maxPowerExponent = 1000
+
+var decimalOne = decimal.NewFromInt32(1)
+
+func Abs(accessor NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() {
+		return nil
+	}
+	if IsInteger(accessor) {
+		v := accessor.Int64()
+		if v < 0 {
+			v = -v
+		}
+		return newIntegerResult(v)
+	}
+	return NewDecimal(accessor.Decimal().Abs())
+}
+
+func Ceiling(accessor NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() {
+		return nil
+	}
+	return newIntegerDecimalResult(accessor.Decimal().Ceil())
+}
+
+func Floor(accessor NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() {
+		return nil
+	}
+	return newIntegerDecimalResult(accessor.Decimal().Floor())
+}
+
+func Truncate(accessor NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() {
+		return nil
+	}
+	return newIntegerDecimalResult(accessor.Decimal().Truncate(0))
+}
+
+func Round(accessor NumberAccessor, precision int32) (NumberAccessor, error) {
+	if precision < 0 || precision > maxDecimalPrecision {
+		return nil, fmt.Errorf("not a valid rounding precision: %d", precision)
+	}
+	if accessor == nil || accessor.Nil() {
+		return nil, nil
+	}
+	return newDecimalResult(accessor.Decimal().Round(precision)), nil
+}
+
+func Exp(accessor NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() {
+		return nil
+	}
+	return newDecimalFloatResult(math.Exp(accessor.Float64()))
+}
+
+func Ln(accessor NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() || accessor.Decimal().Sign() <= 0 {
+		return nil
+	}
+	return newDecimalFloatResult(math.Log(accessor.Float64()))
+}
+
+func Log(accessor NumberAccessor, base NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() || base == nil || base.Nil() {
+		return nil
+	}
+	if accessor.Decimal().Sign() <= 0 || base.Decimal().Sign() <= 0 || base.Decimal().Equal(decimalOne) {
+		return nil
+	}
+	return newDecimalFloatResult(math.Log(accessor.Float64()) / math.Log(base.Float64()))
+}
+
+func Power(accessor NumberAccessor, exponent NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() || exponent == nil || exponent.Nil() {
+		return nil
+	}
+	if IsInteger(accessor) && IsInteger(exponent) {
+		return integerPower(accessor.Int64(), exponent.Int64())
+	}
+	return decimalPower(accessor.Decimal(), exponent.Decimal())
+}
+
+func integerPower(base int64, exponent int64) NumberAccessor {
+	if exponent < 0 {
+		switch {
+		case base == 1:
+			return NewInteger(1)
+		case base == -1 && exponent%2 == 0:
+			return NewInteger(1)
+		case base == -1:
+			return NewInteger(-1)
+		}
+		return nil
+	}
+
+	result := int64(1)
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result = result * base
+			if result < math.MinInt32 || result > math.MaxInt32 {
+				return nil
+			}
+		}
+		exponent = exponent >> 1
+		if exponent > 0 {
+			base = base * base
+			if base > math.MaxInt32 {
+				return nil
+			}
+		}
+	}
+	return NewInteger(int32(result))
+}
+
+func decimalPower(base decimal.Decimal, exponent decimal.Decimal) NumberAccessor {
+	if !exponent.Equal(exponent.Truncate(0)) {
+		if base.Sign() < 0 {
+			return nil
+		}
+		b, _ := base.Float64()
+		e, _ := exponent.Float64()
+		return newDecimalFloatResult(math.Pow(b, e))
+	}
+
+	if exponent.Abs().GreaterThan(decimal.NewFromInt32(maxPowerExponent)) {
+		return nil
+	}
+	if base.IsZero() {
+		if exponent.Sign() < 0 {
+			return nil
+		}
+		return newDecimalResult(base.Pow(exponent))
+	}
+
+	b, _ := base.Abs().Float64()
+	e, _ := exponent.Abs().Float64()
+	if math.Log10(b)*e > 20 {
+		return nil
+	}
+	if exponent.Sign() < 0 {
+		return newDecimalResult(trimDecimalScale(
+			decimalOne.DivRound(base.Pow(exponent.Neg()), divisionPrecision), 0))
+	}
+	return newDecimalResult(base.Pow(exponent))
+}
+
+func Sqrt(accessor NumberAccessor) NumberAccessor {
+	if accessor == nil || accessor.Nil() || accessor.Decimal().Sign() < 0 {
+		return nil
+	}
+
+	f, _ := new(big.Float).SetPrec(256).SetString(accessor.Decimal().String())
+	d, err := decimal.NewFromString(f.Sqrt(f).Text('f', divisionPrecision))
+	if err != nil {
+		return nil
+	}
+	return newDecimalResult(trimDecimalScale(d, 0))
+}
+
+func newIntegerDecimalResult(value decimal.Decimal) NumberAccessor {
+	if !value.BigInt().IsInt64() {
+		return nil
+	}
+	return newIntegerResult(value.IntPart())
+}
+
+func newDecimalFloatResult(value float64) NumberAccessor {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return newDecimalResult(trimDecimalScale(decimal.NewFromFloat(value).Round(divisionPrecision), 0))
+}
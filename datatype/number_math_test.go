@@ -0,0 +1,240 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func mustParseDecimal(t *testing.T, value string) DecimalAccessor {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestMathFunctionsEmpty(t *testing.T) {
+	for _, f := range []func(NumberAccessor) NumberAccessor{Abs, Ceiling, Floor, Truncate, Exp, Ln, Sqrt} {
+		assert.Nil(t, f(nil))
+		assert.Nil(t, f(NewDecimalNil()))
+	}
+	assert.Nil(t, Log(nil, NewInteger(10)))
+	assert.Nil(t, Log(NewInteger(10), NewIntegerNil()))
+	assert.Nil(t, Power(nil, NewInteger(2)))
+	assert.Nil(t, Power(NewInteger(2), NewIntegerNil()))
+	r, err := Round(NewDecimalNil(), 2)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r)
+}
+
+func TestAbs(t *testing.T) {
+	if r := Abs(NewInteger(-5)); assert.NotNil(t, r) {
+		assert.Equal(t, IntegerDataType, r.DataType())
+		assert.Equal(t, "5", r.String())
+	}
+	if r := Abs(mustParseDecimal(t, "-5.50")); assert.NotNil(t, r) {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "5.50", r.String())
+	}
+	assert.Nil(t, Abs(NewInteger(math.MinInt32)))
+}
+
+func TestCeiling(t *testing.T) {
+	for value, expected := range map[string]int32{"1": 1, "2.1": 3, "-2.1": -2, "-0.5": 0} {
+		if r := Ceiling(mustParseDecimal(t, value)); assert.NotNil(t, r, "result expected for %s", value) {
+			assert.Equal(t, IntegerDataType, r.DataType())
+			assert.Equal(t, expected, r.Int(), "unexpected result for %s", value)
+		}
+	}
+	assert.Nil(t, Ceiling(mustParseDecimal(t, "2147483647.1")))
+	assert.Nil(t, Ceiling(mustParseDecimal(t, "92233720368547758070")))
+}
+
+func TestFloor(t *testing.T) {
+	for value, expected := range map[string]int32{"1": 1, "2.1": 2, "-2.1": -3} {
+		if r := Floor(mustParseDecimal(t, value)); assert.NotNil(t, r, "result expected for %s", value) {
+			assert.Equal(t, IntegerDataType, r.DataType())
+			assert.Equal(t, expected, r.Int(), "unexpected result for %s", value)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	for value, expected := range map[string]int32{"101": 101, "1.00000001": 1, "-1.56": -1} {
+		if r := Truncate(mustParseDecimal(t, value)); assert.NotNil(t, r, "result expected for %s", value) {
+			assert.Equal(t, IntegerDataType, r.DataType())
+			assert.Equal(t, expected, r.Int(), "unexpected result for %s", value)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value     NumberAccessor
+		precision int32
+		expected  string
+	}{
+		{NewInteger(1), 0, "1"},
+		{mustParseDecimal(t, "3.14159"), 3, "3.142"},
+		{mustParseDecimal(t, "2.5"), 0, "3"},
+		{mustParseDecimal(t, "-2.5"), 0, "-3"},
+		{mustParseDecimal(t, "1.5"), 2, "1.50"},
+	}
+	for _, test := range tests {
+		r, err := Round(test.value, test.precision)
+		assert.NoError(t, err, "no error expected")
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, DecimalDataType, r.DataType())
+			assert.Equal(t, test.expected, r.String())
+		}
+	}
+}
+
+func TestRoundInvalidPrecision(t *testing.T) {
+	r, err := Round(NewInteger(1), -1)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+}
+
+func TestExp(t *testing.T) {
+	if r := Exp(NewInteger(0)); assert.NotNil(t, r) {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "1", r.String())
+	}
+	if r := Exp(mustParseDecimal(t, "-0.0")); assert.NotNil(t, r) {
+		assert.Equal(t, "1", r.String())
+	}
+	if r := Exp(NewInteger(1)); assert.NotNil(t, r) {
+		assert.Equal(t, "2.71828183", r.String())
+	}
+	assert.Nil(t, Exp(NewInteger(1000)))
+}
+
+func TestLn(t *testing.T) {
+	if r := Ln(NewInteger(1)); assert.NotNil(t, r) {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "0", r.String())
+	}
+	if r := Ln(mustParseDecimal(t, "1.0")); assert.NotNil(t, r) {
+		assert.Equal(t, "0", r.String())
+	}
+	if r := Ln(NewInteger(10)); assert.NotNil(t, r) {
+		assert.Equal(t, "2.30258509", r.String())
+	}
+	assert.Nil(t, Ln(NewInteger(0)))
+	assert.Nil(t, Ln(NewInteger(-1)))
+}
+
+func TestLog(t *testing.T) {
+	if r := Log(NewInteger(16), NewInteger(2)); assert.NotNil(t, r) {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "4", r.String())
+	}
+	if r := Log(mustParseDecimal(t, "100.0"), NewInteger(10)); assert.NotNil(t, r) {
+		assert.Equal(t, "2", r.String())
+	}
+	assert.Nil(t, Log(NewInteger(0), NewInteger(10)))
+	assert.Nil(t, Log(NewInteger(10), NewInteger(0)))
+	assert.Nil(t, Log(NewInteger(10), NewInteger(1)))
+}
+
+func TestPowerInteger(t *testing.T) {
+	tests := []struct {
+		base     int32
+		exponent int32
+		expected int32
+	}{
+		{2, 3, 8},
+		{-2, 3, -8},
+		{7, 0, 1},
+		{0, 0, 1},
+		{1, -5, 1},
+		{-1, -5, -1},
+		{-1, -4, 1},
+		{2, 30, 1073741824},
+		{-2, 31, math.MinInt32},
+	}
+	for _, test := range tests {
+		if r := Power(NewInteger(test.base), NewInteger(test.exponent)); assert.NotNil(t, r, "result expected for %d^%d", test.base, test.exponent) {
+			assert.Equal(t, IntegerDataType, r.DataType())
+			assert.Equal(t, test.expected, r.Int(), "unexpected result for %d^%d", test.base, test.exponent)
+		}
+	}
+}
+
+func TestPowerIntegerEmpty(t *testing.T) {
+	assert.Nil(t, Power(NewInteger(2), NewInteger(31)))
+	assert.Nil(t, Power(NewInteger(2), NewInteger(-1)))
+	assert.Nil(t, Power(NewInteger(65536), NewInteger(4)))
+	assert.Nil(t, Power(NewInteger(0), NewInteger(-1)))
+}
+
+func TestPowerDecimal(t *testing.T) {
+	tests := []struct {
+		base     NumberAccessor
+		exponent NumberAccessor
+		expected string
+	}{
+		{mustParseDecimal(t, "2.5"), NewInteger(2), "6.25"},
+		{NewInteger(2), mustParseDecimal(t, "0.5"), "1.41421356"},
+		{NewInteger(2), mustParseDecimal(t, "-2.0"), "0.25"},
+		{mustParseDecimal(t, "-1.5"), NewInteger(3), "-3.375"},
+		{mustParseDecimal(t, "0.0"), NewInteger(3), "0.000"},
+	}
+	for _, test := range tests {
+		if r := Power(test.base, test.exponent); assert.NotNil(t, r, "result expected for %s^%s", test.base, test.exponent) {
+			assert.Equal(t, DecimalDataType, r.DataType())
+			assert.Equal(t, test.expected, r.String())
+		}
+	}
+}
+
+func TestPowerDecimalEmpty(t *testing.T) {
+	assert.Nil(t, Power(NewInteger(-1), mustParseDecimal(t, "0.5")))
+	assert.Nil(t, Power(mustParseDecimal(t, "0.0"), NewInteger(-1)))
+	assert.Nil(t, Power(mustParseDecimal(t, "10.0"), NewInteger(21)))
+	assert.Nil(t, Power(mustParseDecimal(t, "1.0"), NewInteger(1001)))
+}
+
+func TestSqrt(t *testing.T) {
+	if r := Sqrt(NewInteger(81)); assert.NotNil(t, r) {
+		assert.Equal(t, DecimalDataType, r.DataType())
+		assert.Equal(t, "9", r.String())
+	}
+	if r := Sqrt(NewInteger(2)); assert.NotNil(t, r) {
+		assert.Equal(t, "1.41421356", r.String())
+	}
+	if r := Sqrt(mustParseDecimal(t, "0.0001")); assert.NotNil(t, r) {
+		assert.Equal(t, "0.01", r.String())
+	}
+	assert.Nil(t, Sqrt(NewInteger(-1)))
+}
@@ -89,3 +89,49 @@ func TestDecimalPrecisionZeros(t *testing.T) {
 	}
 	assert.Equal(t, int32(0), decimalPrecision(v))
 }
+
+func TestCompareNumbersNil(t *testing.T) {
+	assert.Equal(t, EmptyComparisonResult, CompareNumbers(nil, NewInteger(1)))
+	assert.Equal(t, EmptyComparisonResult, CompareNumbers(NewInteger(1), nil))
+	assert.Equal(t, EmptyComparisonResult, CompareNumbers(NewIntegerNil(), NewInteger(1)))
+	assert.Equal(t, EmptyComparisonResult, CompareNumbers(NewDecimalInt(1), NewDecimalNil()))
+}
+
+func TestCompareNumbersInteger(t *testing.T) {
+	assert.Equal(t, LessComparisonResult, CompareNumbers(NewInteger(-8), NewInteger(3)))
+	assert.Equal(t, EqualComparisonResult, CompareNumbers(NewInteger(3), NewInteger(3)))
+	assert.Equal(t, GreaterComparisonResult, CompareNumbers(NewInteger(4), NewInteger(3)))
+}
+
+func TestCompareNumbersIntegerSubtypes(t *testing.T) {
+	assert.Equal(t, LessComparisonResult, CompareNumbers(NewUnsignedInt(0), NewPositiveInt(1)))
+	assert.Equal(t, EqualComparisonResult, CompareNumbers(NewPositiveInt(7), NewInteger(7)))
+	assert.Equal(t, GreaterComparisonResult, CompareNumbers(NewPositiveInt(7), NewUnsignedInt(6)))
+}
+
+func TestCompareNumbersDecimal(t *testing.T) {
+	d1, _ := ParseDecimal("0.30000000000000000001")
+	d2, _ := ParseDecimal("0.3")
+	assert.Equal(t, GreaterComparisonResult, CompareNumbers(d1, d2))
+	assert.Equal(t, LessComparisonResult, CompareNumbers(d2, d1))
+}
+
+func TestCompareNumbersDecimalTrailingZeros(t *testing.T) {
+	d1, _ := ParseDecimal("1.0")
+	d2, _ := ParseDecimal("1.000")
+	assert.Equal(t, EqualComparisonResult, CompareNumbers(d1, d2))
+}
+
+func TestCompareNumbersMixed(t *testing.T) {
+	assert.Equal(t, LessComparisonResult, CompareNumbers(NewInteger(2), NewDecimalFloat64(2.5)))
+	assert.Equal(t, EqualComparisonResult, CompareNumbers(NewDecimalInt(2), NewInteger(2)))
+	assert.Equal(t, GreaterComparisonResult, CompareNumbers(NewDecimalFloat64(2.5), NewUnsignedInt(2)))
+}
+
+func TestDecimalPrecisionLargeScale(t *testing.T) {
+	v, err := decimal.NewFromString("0.10000000000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int32(29), decimalPrecision(v))
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type ParseErrorReasons int
+
+const (
+	InvalidSyntaxParseErrorReason ParseErrorReasons = iota + 1
+	OutOfRangeParseErrorReason
+	UnsupportedUnitParseErrorReason
+)
+
+type ParseError struct {
+	dataType DataTypes
+	value    string
+	position int
+	reason   ParseErrorReasons
+}
+
+func NewParseError(dataType DataTypes, value string, position int, reason ParseErrorReasons) *ParseError {
+	return &ParseError{
+		dataType: dataType,
+		value:    value,
+		position: position,
+		reason:   reason,
+	}
+}
+
+func newSyntaxParseError(dataType DataTypes, value string, position int) *ParseError {
+	return NewParseError(dataType, value, position, InvalidSyntaxParseErrorReason)
+}
+
+func (r ParseErrorReasons) String() string {
+	switch r {
+	case InvalidSyntaxParseErrorReason:
+		return "invalid syntax"
+	case OutOfRangeParseErrorReason:
+		return "value out of range"
+	case UnsupportedUnitParseErrorReason:
+		return "unsupported unit"
+	}
+	return "reason " + strconv.Itoa(int(r))
+}
+
+func (e *ParseError) DataType() DataTypes {
+	return e.dataType
+}
+
+func (e *ParseError) Value() string {
+	return e.value
+}
+
+func (e *ParseError) Position() int {
+	return e.position
+}
+
+func (e *ParseError) Reason() ParseErrorReasons {
+	return e.reason
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("not a valid %s: %s (%s at position %d)",
+		dataTypeName(e.dataType), e.value, e.reason, e.position)
+}
+
+func dataTypeName(dataType DataTypes) string {
+	var typeSpec TypeSpecAccessor
+	switch dataType {
+	case BooleanDataType:
+		typeSpec = booleanTypeSpec
+	case IntegerDataType:
+		typeSpec = integerTypeSpec
+	case StringDataType:
+		typeSpec = stringTypeSpec
+	case DecimalDataType:
+		typeSpec = decimalTypeSpec
+	case URIDataType:
+		typeSpec = uriTypeSpec
+	case DateDataType:
+		typeSpec = dateTypeSpec
+	case DateTimeDataType:
+		typeSpec = dateTimeTypeSpec
+	case TimeDataType:
+		typeSpec = timeTypeSpec
+	case CodeDataType:
+		typeSpec = codeTypeSpec
+	case IDDataType:
+		typeSpec = idTypeSpec
+	case MarkdownDataType:
+		typeSpec = markdownTypeSpec
+	case UnsignedIntDataType:
+		typeSpec = unsignedIntTypeSpec
+	case PositiveIntDataType:
+		typeSpec = positiveIntTypeSpec
+	case QuantityDataType:
+		typeSpec = quantityTypeSpec
+	default:
+		return "value"
+	}
+	return typeSpec.FQName().Name()
+}
+
+func commonPrefixLength(value string, candidates ...string) int {
+	max := 0
+	for _, c := range candidates {
+		l := 0
+		for l < len(value) && l < len(c) && value[l] == c[l] {
+			l++
+		}
+		if l > max {
+			max = l
+		}
+	}
+	return max
+}
+
+func digitsEnd(value string, pos int) int {
+	for pos < len(value) && value[pos] >= '0' && value[pos] <= '9' {
+		pos++
+	}
+	return pos
+}
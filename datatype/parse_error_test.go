@@ -0,0 +1,101 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func assertParseError(t *testing.T, err error, dataType DataTypes, position int, reason ParseErrorReasons) {
+	var e *ParseError
+	if assert.True(t, errors.As(err, &e), "parse error expected: %v", err) {
+		assert.Equal(t, dataType, e.DataType(), "unexpected data type")
+		assert.Equal(t, position, e.Position(), "unexpected position for %s", e.Value())
+		assert.Equal(t, reason, e.Reason(), "unexpected reason for %s", e.Value())
+	}
+}
+
+func TestNewParseError(t *testing.T) {
+	e := NewParseError(DateDataType, "2020-13", 5, OutOfRangeParseErrorReason)
+	assert.Equal(t, DateDataType, e.DataType())
+	assert.Equal(t, "2020-13", e.Value())
+	assert.Equal(t, 5, e.Position())
+	assert.Equal(t, OutOfRangeParseErrorReason, e.Reason())
+	assert.Equal(t, "not a valid date: 2020-13 (value out of range at position 5)", e.Error())
+}
+
+func TestParseErrorAs(t *testing.T) {
+	err := fmt.Errorf("mapping failed: %w", NewParseError(CodeDataType, " x", 0, InvalidSyntaxParseErrorReason))
+	assertParseError(t, err, CodeDataType, 0, InvalidSyntaxParseErrorReason)
+}
+
+func TestParseErrorReasonsString(t *testing.T) {
+	assert.Equal(t, "invalid syntax", InvalidSyntaxParseErrorReason.String())
+	assert.Equal(t, "value out of range", OutOfRangeParseErrorReason.String())
+	assert.Equal(t, "unsupported unit", UnsupportedUnitParseErrorReason.String())
+	assert.Equal(t, "reason 99", ParseErrorReasons(99).String())
+}
+
+func TestParseErrorDataTypeName(t *testing.T) {
+	names := map[DataTypes]string{
+		BooleanDataType:     "boolean",
+		IntegerDataType:     "integer",
+		StringDataType:      "string",
+		DecimalDataType:     "decimal",
+		URIDataType:         "uri",
+		DateDataType:        "date",
+		DateTimeDataType:    "dateTime",
+		TimeDataType:        "time",
+		CodeDataType:        "code",
+		IDDataType:          "id",
+		MarkdownDataType:    "markdown",
+		UnsignedIntDataType: "unsignedInt",
+		PositiveIntDataType: "positiveInt",
+		QuantityDataType:    "Quantity",
+		UndefinedDataType:   "value",
+	}
+	for dataType, name := range names {
+		assert.Equal(t, name, dataTypeName(dataType))
+	}
+}
+
+func TestCommonPrefixLength(t *testing.T) {
+	assert.Equal(t, 0, commonPrefixLength("x", "true", "false"))
+	assert.Equal(t, 3, commonPrefixLength("fal", "true", "false"))
+	assert.Equal(t, 4, commonPrefixLength("truex", "true", "false"))
+}
+
+func TestDigitsEnd(t *testing.T) {
+	assert.Equal(t, 4, digitsEnd("-123a", 1))
+	assert.Equal(t, 0, digitsEnd("a", 0))
+	assert.Equal(t, 3, digitsEnd("123", 0))
+}
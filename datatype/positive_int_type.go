@@ -28,6 +28,8 @@
 
 package datatype
 
+import "strconv"
+
 var positiveIntTypeSpec = newElementTypeSpecWithBase("positiveInt", integerTypeSpec)
 
 type positiveIntType struct {
@@ -44,11 +46,21 @@ func NewPositiveIntNil() PositiveIntAccessor {
 
 func NewPositiveInt(value int32) PositiveIntAccessor {
 	if value <= 0 {
-		panic("datatype: positive int must be positive")
+		panic(NewParseError(PositiveIntDataType, strconv.Itoa(int(value)), 0, OutOfRangeParseErrorReason))
 	}
 	return newPositiveInt(false, value)
 }
 
+func ParsePositiveInt(value string) (PositiveIntAccessor, error) {
+	if i, err := parseStrictInteger(PositiveIntDataType, value, false); err != nil {
+		return nil, err
+	} else if i == 0 {
+		return nil, NewParseError(PositiveIntDataType, value, 0, OutOfRangeParseErrorReason)
+	} else {
+		return newPositiveInt(false, i), nil
+	}
+}
+
 func newPositiveInt(nilValue bool, value int32) PositiveIntAccessor {
 	return &positiveIntType{
 		integerType{
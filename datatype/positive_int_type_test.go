@@ -30,6 +30,7 @@ package datatype
 
 import (
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -102,3 +103,40 @@ func TestPositiveIntEqualInteger(t *testing.T) {
 	assert.Equal(t, true, NewPositiveInt(8274).Equal(NewInteger(8274)))
 	assert.Equal(t, true, NewPositiveInt(8274).Equivalent(NewInteger(8274)))
 }
+
+func TestPositiveIntInvalidPanicsWithParseError(t *testing.T) {
+	defer func() {
+		assertParseError(t, recover().(error), PositiveIntDataType, 0, OutOfRangeParseErrorReason)
+	}()
+	NewPositiveInt(0)
+}
+
+func TestParsePositiveInt(t *testing.T) {
+	for value, expected := range map[string]int32{"1": 1, "10": 10, "2147483647": math.MaxInt32} {
+		o, err := ParsePositiveInt(value)
+		if assert.NoError(t, err, "no error expected for %s", value) {
+			assert.Equal(t, PositiveIntDataType, o.DataType())
+			assert.Equal(t, expected, o.Int())
+		}
+	}
+}
+
+func TestParsePositiveIntInvalid(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		reason   ParseErrorReasons
+	}{
+		{"", 0, InvalidSyntaxParseErrorReason},
+		{"0", 0, OutOfRangeParseErrorReason},
+		{"-1", 0, InvalidSyntaxParseErrorReason},
+		{"+1", 0, InvalidSyntaxParseErrorReason},
+		{"01", 0, InvalidSyntaxParseErrorReason},
+		{"2147483648", 0, OutOfRangeParseErrorReason},
+	}
+	for _, test := range tests {
+		o, err := ParsePositiveInt(test.value)
+		assert.Nil(t, o, "value unexpected")
+		assertParseError(t, err, PositiveIntDataType, test.position, test.reason)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"regexp"
+	"strings"
+)
+
+var quantityLiteralRegexp = regexp.MustCompile("^([+-]?\\d+(?:\\.\\d+)?)(?:\\s*'((?:[^'\\\\]|\\\\.)+)'|\\s+([A-Za-z]+))?$")
+var quantityValueRegexp = regexp.MustCompile("^[+-]?\\d+(?:\\.\\d+)?\\s*")
+
+var calendarDurationCodes = map[string]string{
+	"year":         "a",
+	"years":        "a",
+	"month":        "mo",
+	"months":       "mo",
+	"week":         "wk",
+	"weeks":        "wk",
+	"day":          "d",
+	"days":         "d",
+	"hour":         "h",
+	"hours":        "h",
+	"minute":       "min",
+	"minutes":      "min",
+	"second":       "s",
+	"seconds":      "s",
+	"millisecond":  "ms",
+	"milliseconds": "ms",
+}
+
+func ParseQuantityLiteral(value string) (QuantityAccessor, error) {
+	q, err := parseQuantityLiteral(value)
+	if err != nil {
+		return nil, err
+	}
+	return &systemQuantityType{q}, nil
+}
+
+func parseQuantityLiteral(value string) (QuantityAccessor, error) {
+	parts := quantityLiteralRegexp.FindStringSubmatchIndex(value)
+	if parts == nil {
+		return nil, newSyntaxParseError(QuantityDataType, value, quantityLiteralErrorPosition(value))
+	}
+
+	d, err := ParseDecimal(value[parts[2]:parts[3]])
+	if err != nil {
+		return nil, newSyntaxParseError(QuantityDataType, value, parts[2])
+	}
+
+	if parts[6] >= 0 {
+		unit := value[parts[6]:parts[7]]
+		code, found := calendarDurationCodes[unit]
+		if !found {
+			return nil, NewParseError(QuantityDataType, value, parts[6], UnsupportedUnitParseErrorReason)
+		}
+		return NewQuantity(d, nil, NewString(unit), UCUMSystemURI, NewCode(code)), nil
+	}
+	if parts[4] >= 0 {
+		unit, ok := unescapeStringLiteral(value[parts[4]:parts[5]])
+		if !ok {
+			return nil, newSyntaxParseError(QuantityDataType, value, parts[4])
+		}
+		code, err := ParseCode(unit)
+		if err != nil {
+			return nil, newSyntaxParseError(QuantityDataType, value, parts[4])
+		}
+		return NewQuantity(d, nil, NewString(unit), UCUMSystemURI, code), nil
+	}
+	return NewQuantity(d, nil, nil, nil, nil), nil
+}
+
+func quantityLiteralErrorPosition(value string) int {
+	if loc := quantityValueRegexp.FindStringIndex(value); loc != nil {
+		return loc[1]
+	}
+	return 0
+}
+
+func FormatQuantityLiteral(accessor QuantityAccessor) string {
+	if accessor == nil {
+		return ""
+	}
+	if value := accessor.Value(); value == nil || value.Nil() {
+		return ""
+	}
+	return formatQuantity(accessor)
+}
+
+func formatQuantity(accessor QuantityAccessor) string {
+	var b strings.Builder
+	b.Grow(32)
+	if value := accessor.Value(); value != nil && !value.Nil() {
+		b.WriteString(value.String())
+	}
+
+	code := accessor.Code()
+	if code == nil || code.Nil() {
+		return b.String()
+	}
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	if unit, ok := calendarDurationUnit(accessor); ok {
+		b.WriteString(unit)
+	} else {
+		b.WriteByte('\'')
+		writeStringBuilderEscaped(&b, code.String())
+		b.WriteByte('\'')
+	}
+	return b.String()
+}
+
+func calendarDurationUnit(accessor QuantityAccessor) (string, bool) {
+	system := accessor.System()
+	if system != nil && !system.Nil() && system.String() != UCUMSystemURI.String() {
+		return "", false
+	}
+
+	unit := StringValue(accessor.Unit())
+	code, found := calendarDurationCodes[unit]
+	if !found || code != StringValue(accessor.Code()) {
+		return "", false
+	}
+	return unit, true
+}
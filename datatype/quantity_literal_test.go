@@ -0,0 +1,210 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseQuantityLiteralUCUM(t *testing.T) {
+	q, err := ParseQuantityLiteral("4.5 'mg'")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, q, "quantity expected") {
+		assert.Equal(t, "4.5", q.Value().String())
+		assert.Nil(t, q.Comparator())
+		assert.Equal(t, "mg", q.Unit().String())
+		assert.Equal(t, UCUMSystemURI, q.System())
+		assert.Equal(t, "mg", q.Code().String())
+	}
+}
+
+func TestParseQuantityLiteralTypeSpec(t *testing.T) {
+	q, err := ParseQuantityLiteral("4.5 'mg'")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, q, "quantity expected") {
+		assert.Equal(t, QuantityDataType, q.DataType())
+		assert.Equal(t, "System.Quantity", q.TypeSpec().String())
+		assert.Equal(t, "FHIR.Quantity", FHIRValueOf(q).TypeSpec().String())
+	}
+}
+
+func TestParseQuantityLiteralUCUMNoSpace(t *testing.T) {
+	q, err := ParseQuantityLiteral("-10'cm'")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, q, "quantity expected") {
+		assert.Equal(t, "-10", q.Value().String())
+		assert.Equal(t, "cm", q.Code().String())
+	}
+}
+
+func TestParseQuantityLiteralUCUMEscaped(t *testing.T) {
+	q, err := ParseQuantityLiteral("1 '\\'m\\u0027'")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, q, "quantity expected") {
+		assert.Equal(t, "'m'", q.Code().String())
+	}
+}
+
+func TestParseQuantityLiteralUCUMInvalidEscape(t *testing.T) {
+	q, err := ParseQuantityLiteral("1 'm\\x'")
+	if assert.IsType(t, &ParseError{}, err, "parse error expected") {
+		assert.Equal(t, 3, err.(*ParseError).Position())
+	}
+	assert.Nil(t, q, "no quantity expected")
+}
+
+func TestParseQuantityLiteralUCUMInvalidCode(t *testing.T) {
+	q, err := ParseQuantityLiteral("1 'm  g'")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, q, "no quantity expected")
+}
+
+func TestParseQuantityLiteralCalendarDuration(t *testing.T) {
+	q, err := ParseQuantityLiteral("3 days")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, q, "quantity expected") {
+		assert.Equal(t, "3", q.Value().String())
+		assert.Equal(t, "days", q.Unit().String())
+		assert.Equal(t, UCUMSystemURI, q.System())
+		assert.Equal(t, "d", q.Code().String())
+	}
+}
+
+func TestParseQuantityLiteralCalendarDurations(t *testing.T) {
+	for unit, code := range map[string]string{
+		"year": "a", "years": "a", "month": "mo", "months": "mo",
+		"week": "wk", "weeks": "wk", "day": "d", "days": "d",
+		"hour": "h", "hours": "h", "minute": "min", "minutes": "min",
+		"second": "s", "seconds": "s", "millisecond": "ms", "milliseconds": "ms",
+	} {
+		q, err := ParseQuantityLiteral("1 " + unit)
+		assert.NoError(t, err, "no error expected for %s", unit)
+		if assert.NotNil(t, q, "quantity expected for %s", unit) {
+			assert.Equal(t, code, q.Code().String())
+		}
+	}
+}
+
+func TestParseQuantityLiteralInvalidCalendarDuration(t *testing.T) {
+	q, err := ParseQuantityLiteral("2 fortnights")
+	if assert.IsType(t, &ParseError{}, err, "parse error expected") {
+		e := err.(*ParseError)
+		assert.Equal(t, QuantityDataType, e.DataType())
+		assert.Equal(t, "2 fortnights", e.Value())
+		assert.Equal(t, 2, e.Position())
+		assert.Equal(t, UnsupportedUnitParseErrorReason, e.Reason())
+	}
+	assert.Nil(t, q, "no quantity expected")
+}
+
+func TestParseQuantityLiteralValueOnly(t *testing.T) {
+	q, err := ParseQuantityLiteral("17.20")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, q, "quantity expected") {
+		assert.Equal(t, "17.20", q.Value().String())
+		assert.Nil(t, q.Unit())
+		assert.Nil(t, q.System())
+		assert.Nil(t, q.Code())
+	}
+}
+
+func TestParseQuantityLiteralInvalid(t *testing.T) {
+	q, err := ParseQuantityLiteral("mg 4.5")
+	if assert.IsType(t, &ParseError{}, err, "parse error expected") {
+		e := err.(*ParseError)
+		assert.Equal(t, QuantityDataType, e.DataType())
+		assert.Equal(t, 0, e.Position())
+		assert.Equal(t, InvalidSyntaxParseErrorReason, e.Reason())
+	}
+	assert.Nil(t, q, "no quantity expected")
+}
+
+func TestFormatQuantityLiteralNil(t *testing.T) {
+	assert.Equal(t, "", FormatQuantityLiteral(nil))
+}
+
+func TestFormatQuantityLiteralNilValue(t *testing.T) {
+	q := NewQuantity(NewDecimalNil(), nil, nil, UCUMSystemURI, NewCode("mg"))
+	assert.Equal(t, "", FormatQuantityLiteral(q))
+	assert.Equal(t, "'mg'", q.String())
+}
+
+func TestFormatQuantityLiteralRoundTrip(t *testing.T) {
+	for _, value := range []string{"2", "2.50 'mg'", "-1 'm\\'s'", "3 days", "1 '\\uD83D\\uDE00'"} {
+		q, err := ParseQuantityLiteral(value)
+		if assert.NoError(t, err, "unexpected error for %s", value) {
+			r, err := ParseQuantityLiteral(FormatQuantityLiteral(q))
+			if assert.NoError(t, err, "unexpected error for %s", value) {
+				assert.True(t, q.Equal(r), "round trip failed for %s", value)
+			}
+		}
+	}
+}
+
+func TestFormatQuantityLiteralEscaped(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(2), nil, nil, UCUMSystemURI, NewCode("'m'"))
+	assert.Equal(t, "2 '\\'m\\''", FormatQuantityLiteral(q))
+}
+
+func TestFormatQuantityLiteralCalendarCodeDiffers(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(2), nil, NewString("days"), UCUMSystemURI, NewCode("h"))
+	assert.Equal(t, "2 'h'", FormatQuantityLiteral(q))
+}
+
+func TestFormatQuantityLiteralCalendarOtherSystem(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(2), nil, NewString("days"), NewURI("urn:test"), NewCode("d"))
+	assert.Equal(t, "2 'd'", FormatQuantityLiteral(q))
+}
+
+func TestFormatQuantityLiteralCalendarNoSystem(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(2), nil, NewString("days"), nil, NewCode("d"))
+	assert.Equal(t, "2 days", FormatQuantityLiteral(q))
+}
+
+func TestQuantityLiteralRoundTrip(t *testing.T) {
+	for _, literal := range []string{"4.5 'mg'", "3 days", "1 year", "-0.010 'mmol/L'", "1 'a'", "12"} {
+		q, err := ParseQuantityLiteral(literal)
+		if assert.NoError(t, err, "no error expected for %s", literal) {
+			assert.Equal(t, literal, FormatQuantityLiteral(q))
+			p, err := ParseQuantityLiteral(q.String())
+			if assert.NoError(t, err, "no error expected for %s", literal) {
+				assert.True(t, q.Equal(p), "round trip failed for %s", literal)
+			}
+		}
+	}
+}
+
+func TestParseQuantityLiteralInvalidUnitPosition(t *testing.T) {
+	q, err := ParseQuantityLiteral("4.5 mg'")
+	if assert.IsType(t, &ParseError{}, err, "parse error expected") {
+		assert.Equal(t, 4, err.(*ParseError).Position())
+	}
+	assert.Nil(t, q, "no quantity expected")
+}
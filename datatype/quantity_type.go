@@ -28,10 +28,6 @@
 
 package datatype
 
-import (
-	"strings"
-)
-
 type QuantityComparator CodeAccessor
 
 var (
@@ -43,7 +39,12 @@ var (
 
 var UCUMSystemURI = NewURI("http://unitsofmeasure.org")
 
-var quantityTypeSpec = newElementTypeSpec("Quantity")
+var quantityTypeSpec = newElementTypeSpec("Quantity").SetProperties(
+	NewTypeSpecProperty("value", 0, 1, SummaryPropertyFlag, decimalTypeSpec),
+	NewTypeSpecProperty("comparator", 0, 1, ModifierPropertyFlag|SummaryPropertyFlag, codeTypeSpec),
+	NewTypeSpecProperty("unit", 0, 1, SummaryPropertyFlag, stringTypeSpec),
+	NewTypeSpecProperty("system", 0, 1, SummaryPropertyFlag, uriTypeSpec),
+	NewTypeSpecProperty("code", 0, 1, SummaryPropertyFlag, codeTypeSpec))
 
 type quantityType struct {
 	value      DecimalAccessor
@@ -180,16 +181,5 @@ func quantityEqual(t QuantityAccessor, accessor Accessor) bool {
 }
 
 func (t *quantityType) String() string {
-	var b strings.Builder
-	b.Grow(32)
-	if t.value != nil {
-		b.WriteString(t.value.String())
-	}
-	if t.code != nil {
-		if b.Len() > 0 {
-			b.WriteByte(' ')
-		}
-		b.WriteString(t.code.String())
-	}
-	return b.String()
+	return formatQuantity(t)
 }
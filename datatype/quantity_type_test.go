@@ -250,11 +250,41 @@ func TestQuantityStringValueOnly(t *testing.T) {
 func TestQuantityString(t *testing.T) {
 	q := NewQuantity(NewDecimalFloat64(47.1), LessThanQuantityComparator,
 		NewString("gram"), UCUMSystemURI, NewCode("g"))
-	assert.Equal(t, "47.1 g", q.String())
+	assert.Equal(t, "47.1 'g'", q.String())
 }
 
 func TestQuantityStringCodeOnly(t *testing.T) {
 	q := NewQuantity(nil, LessThanQuantityComparator,
 		NewString("gram"), UCUMSystemURI, NewCode("g"))
-	assert.Equal(t, "g", q.String())
+	assert.Equal(t, "'g'", q.String())
+}
+
+func TestQuantityStringCalendarDuration(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(3), nil,
+		NewString("days"), UCUMSystemURI, NewCode("d"))
+	assert.Equal(t, "3 days", q.String())
+}
+
+func TestQuantityTypeSpecProperties(t *testing.T) {
+	i := NewQuantityEmpty().TypeSpec()
+	names := make([]string, 0)
+	for _, p := range i.Properties() {
+		names = append(names, p.Name())
+	}
+	assert.Equal(t, []string{"id", "extension", "value", "comparator", "unit", "system", "code"}, names)
+
+	value := i.Property("value")
+	if assert.NotNil(t, value, "value property expected") {
+		assert.Equal(t, []TypeSpecAccessor{decimalTypeSpec}, value.TypeSpecs())
+		assert.Equal(t, 0, value.Min())
+		assert.Equal(t, 1, value.Max())
+		assert.False(t, value.Choice())
+		assert.False(t, value.Modifier())
+		assert.True(t, value.Summary())
+	}
+	comparator := i.Property("comparator")
+	if assert.NotNil(t, comparator, "comparator property expected") {
+		assert.Equal(t, []TypeSpecAccessor{codeTypeSpec}, comparator.TypeSpecs())
+		assert.True(t, comparator.Modifier())
+	}
 }
@@ -0,0 +1,128 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Collation interface {
+	Compare(a string, b string) int
+}
+
+type CollationFunc func(a string, b string) int
+
+type simpleCollation struct{}
+
+var SimpleCollation Collation = simpleCollation{}
+
+func (f CollationFunc) Compare(a string, b string) int {
+	return f(a, b)
+}
+
+func CompareStrings(a StringAccessor, b StringAccessor) ComparisonResults {
+	return CompareStringsCollated(a, b, nil)
+}
+
+func CompareStringsCollated(a StringAccessor, b StringAccessor, collation Collation) ComparisonResults {
+	if a == nil || b == nil || a.Nil() || b.Nil() || !IsString(a) || !IsString(b) {
+		return EmptyComparisonResult
+	}
+	if collation == nil {
+		return comparisonResult(strings.Compare(a.String(), b.String()))
+	}
+	return comparisonResult(collation.Compare(a.String(), b.String()))
+}
+
+func (c simpleCollation) Compare(a string, b string) int {
+	if a == b {
+		return 0
+	}
+
+	da, db := decomposeCanonical(a), decomposeCanonical(b)
+	if cmp := compareCollationLevel(da, db, true, true); cmp != 0 {
+		return cmp
+	}
+	if cmp := compareCollationLevel(da, db, false, true); cmp != 0 {
+		return cmp
+	}
+	if cmp := compareCollationCase(da, db); cmp != 0 {
+		return cmp
+	}
+	return compareCollationLevel(da, db, false, false)
+}
+
+func compareCollationLevel(a []rune, b []rune, ignoreMarks bool, ignoreCase bool) int {
+	i, j := 0, 0
+	for {
+		for ignoreMarks && i < len(a) && unicode.Is(unicode.Mn, a[i]) {
+			i++
+		}
+		for ignoreMarks && j < len(b) && unicode.Is(unicode.Mn, b[j]) {
+			j++
+		}
+		if i == len(a) || j == len(b) {
+			switch {
+			case i < len(a):
+				return 1
+			case j < len(b):
+				return -1
+			}
+			return 0
+		}
+
+		ca, cb := a[i], b[j]
+		if ignoreCase {
+			ca, cb = unicode.ToLower(ca), unicode.ToLower(cb)
+		}
+		if ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+		i++
+		j++
+	}
+}
+
+func compareCollationCase(a []rune, b []rune) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if unicode.IsLower(a[i]) && !unicode.IsLower(b[i]) {
+				return -1
+			}
+			if !unicode.IsLower(a[i]) && unicode.IsLower(b[i]) {
+				return 1
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCompareStrings(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected ComparisonResults
+	}{
+		{"abc", "abd", LessComparisonResult},
+		{"abc", "abc", EqualComparisonResult},
+		{"abd", "abc", GreaterComparisonResult},
+		{"ab", "abc", LessComparisonResult},
+		{"", "a", LessComparisonResult},
+		{"B", "a", LessComparisonResult},
+		{"\u00E9", "f", GreaterComparisonResult},
+		{"\uFFFD", "\U0001F600", LessComparisonResult},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, CompareStrings(NewString(test.a), NewString(test.b)),
+			"unexpected result for %q and %q", test.a, test.b)
+	}
+}
+
+func TestCompareStringsStringTypes(t *testing.T) {
+	assert.Equal(t, LessComparisonResult, CompareStrings(NewCode("a"), NewID("b")))
+	assert.Equal(t, EqualComparisonResult, CompareStrings(NewMarkdown("a"), NewString("a")))
+}
+
+func TestCompareStringsEmpty(t *testing.T) {
+	assert.Equal(t, EmptyComparisonResult, CompareStrings(nil, NewString("a")))
+	assert.Equal(t, EmptyComparisonResult, CompareStrings(NewString("a"), nil))
+	assert.Equal(t, EmptyComparisonResult, CompareStrings(NewStringNil(), NewString("a")))
+	assert.Equal(t, EmptyComparisonResult, CompareStrings(NewString("a"), NewCodeNil()))
+	assert.Equal(t, EmptyComparisonResult, CompareStrings(NewString("1"), NewInteger(1)))
+}
+
+func TestCompareStringsCollated(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected ComparisonResults
+	}{
+		{"B", "a", GreaterComparisonResult},
+		{"a", "A", LessComparisonResult},
+		{"\u00E9", "f", LessComparisonResult},
+		{"e", "\u00E9", LessComparisonResult},
+		{"\u00E9", "e\u0301", EqualComparisonResult},
+		{"cote", "c\u00F4te", LessComparisonResult},
+		{"c\u00F4te", "cot\u00E9", GreaterComparisonResult},
+		{"M\u00FCller", "Muller", GreaterComparisonResult},
+		{"M\u00FCller", "Mullers", LessComparisonResult},
+		{"abc", "abc", EqualComparisonResult},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, CompareStringsCollated(NewString(test.a), NewString(test.b), SimpleCollation),
+			"unexpected result for %q and %q", test.a, test.b)
+	}
+}
+
+func TestCompareStringsCollatedSort(t *testing.T) {
+	values := []string{"Zoe", "\u00C9mile", "emma", "Adam", "\u00E9mile", "adam"}
+	sort.Slice(values, func(i, j int) bool {
+		return SimpleCollation.Compare(values[i], values[j]) < 0
+	})
+	assert.Equal(t, "adam,Adam,\u00E9mile,\u00C9mile,emma,Zoe", strings.Join(values, ","))
+}
+
+func TestCompareStringsCollationFunc(t *testing.T) {
+	reverse := CollationFunc(func(a string, b string) int {
+		return strings.Compare(b, a)
+	})
+	assert.Equal(t, GreaterComparisonResult, CompareStringsCollated(NewString("a"), NewString("b"), reverse))
+}
+
+func TestCompareStringsCollatedEmpty(t *testing.T) {
+	assert.Equal(t, EmptyComparisonResult, CompareStringsCollated(nil, NewString("a"), SimpleCollation))
+	assert.Equal(t, EmptyComparisonResult, CompareStringsCollated(NewString("a"), NewStringNil(), SimpleCollation))
+}
@@ -0,0 +1,398 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const maxStringFunctionRegexpCacheSize = 256
+
+var stringFunctionRegexpCache = make(map[string]*regexp.Regexp)
+var stringFunctionRegexpCacheLock sync.RWMutex
+
+func IndexOf(accessor StringAccessor, substring StringAccessor) IntegerAccessor {
+	value, ok1 := stringFunctionValue(accessor)
+	sub, ok2 := stringFunctionValue(substring)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	return NewInteger(codePointIndex(value, strings.Index(value, sub)))
+}
+
+func LastIndexOf(accessor StringAccessor, substring StringAccessor) IntegerAccessor {
+	value, ok1 := stringFunctionValue(accessor)
+	sub, ok2 := stringFunctionValue(substring)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	if sub == "" {
+		return NewInteger(0)
+	}
+	return NewInteger(codePointIndex(value, strings.LastIndex(value, sub)))
+}
+
+func Substring(accessor StringAccessor, start IntegerAccessor, length IntegerAccessor) StringAccessor {
+	value, ok := stringFunctionValue(accessor)
+	if !ok || start == nil || start.Nil() {
+		return nil
+	}
+
+	runes := []rune(value)
+	s := int(start.Int())
+	if s < 0 || s >= len(runes) {
+		return nil
+	}
+	e := len(runes)
+	if length != nil && !length.Nil() {
+		l := int(length.Int())
+		if l < 0 {
+			l = 0
+		}
+		if l < e-s {
+			e = s + l
+		}
+	}
+	return newString(false, string(runes[s:e]))
+}
+
+func StartsWith(accessor StringAccessor, prefix StringAccessor) BooleanAccessor {
+	value, ok1 := stringFunctionValue(accessor)
+	p, ok2 := stringFunctionValue(prefix)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	return NewBoolean(strings.HasPrefix(value, p))
+}
+
+func EndsWith(accessor StringAccessor, suffix StringAccessor) BooleanAccessor {
+	value, ok1 := stringFunctionValue(accessor)
+	s, ok2 := stringFunctionValue(suffix)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	return NewBoolean(strings.HasSuffix(value, s))
+}
+
+func Contains(accessor StringAccessor, substring StringAccessor) BooleanAccessor {
+	value, ok1 := stringFunctionValue(accessor)
+	sub, ok2 := stringFunctionValue(substring)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	return NewBoolean(strings.Contains(value, sub))
+}
+
+func Upper(accessor StringAccessor) StringAccessor {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil
+	}
+	return newString(false, strings.ToUpper(value))
+}
+
+func Lower(accessor StringAccessor) StringAccessor {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil
+	}
+	return newString(false, strings.ToLower(value))
+}
+
+func Replace(accessor StringAccessor, pattern StringAccessor, substitution StringAccessor) StringAccessor {
+	value, ok1 := stringFunctionValue(accessor)
+	p, ok2 := stringFunctionValue(pattern)
+	s, ok3 := stringFunctionValue(substitution)
+	if !ok1 || !ok2 || !ok3 {
+		return nil
+	}
+	return newString(false, strings.ReplaceAll(value, p, s))
+}
+
+func Matches(accessor StringAccessor, regex StringAccessor) (BooleanAccessor, error) {
+	return matchString(accessor, regex, false)
+}
+
+func MatchesFull(accessor StringAccessor, regex StringAccessor) (BooleanAccessor, error) {
+	return matchString(accessor, regex, true)
+}
+
+func matchString(accessor StringAccessor, regex StringAccessor, full bool) (BooleanAccessor, error) {
+	value, ok1 := stringFunctionValue(accessor)
+	r, ok2 := stringFunctionValue(regex)
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+
+	if full {
+		r = "^(?:" + r + ")$"
+	}
+	re, err := compileStringFunctionRegexp(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewBoolean(re.MatchString(value)), nil
+}
+
+func ReplaceMatches(accessor StringAccessor, regex StringAccessor, substitution StringAccessor) (StringAccessor, error) {
+	value, ok1 := stringFunctionValue(accessor)
+	r, ok2 := stringFunctionValue(regex)
+	s, ok3 := stringFunctionValue(substitution)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, nil
+	}
+
+	re, err := compileStringFunctionRegexp(r)
+	if err != nil {
+		return nil, err
+	}
+	return newString(false, re.ReplaceAllString(value, s)), nil
+}
+
+func compileStringFunctionRegexp(regex string) (*regexp.Regexp, error) {
+	stringFunctionRegexpCacheLock.RLock()
+	re, found := stringFunctionRegexpCache[regex]
+	stringFunctionRegexpCacheLock.RUnlock()
+	if found {
+		return re, nil
+	}
+
+	re, err := regexp.Compile("(?s)" + regex)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid regular expression: %s", regex)
+	}
+
+	stringFunctionRegexpCacheLock.Lock()
+	defer stringFunctionRegexpCacheLock.Unlock()
+	if len(stringFunctionRegexpCache) >= maxStringFunctionRegexpCacheSize {
+		stringFunctionRegexpCache = make(map[string]*regexp.Regexp)
+	}
+	stringFunctionRegexpCache[regex] = re
+	return re, nil
+}
+
+func Length(accessor StringAccessor) IntegerAccessor {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil
+	}
+	return NewInteger(int32(utf8.RuneCountInString(value)))
+}
+
+func ToChars(accessor StringAccessor) []StringAccessor {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil
+	}
+
+	result := make([]StringAccessor, 0, len(value))
+	for _, c := range value {
+		result = append(result, newString(false, string(c)))
+	}
+	return result
+}
+
+func Split(accessor StringAccessor, separator StringAccessor) []StringAccessor {
+	value, ok1 := stringFunctionValue(accessor)
+	s, ok2 := stringFunctionValue(separator)
+	if !ok1 || !ok2 {
+		return nil
+	}
+
+	parts := strings.Split(value, s)
+	result := make([]StringAccessor, len(parts))
+	for i, p := range parts {
+		result[i] = newString(false, p)
+	}
+	return result
+}
+
+func Join(accessors []StringAccessor, separator StringAccessor) StringAccessor {
+	if len(accessors) == 0 {
+		return nil
+	}
+	s, ok := stringFunctionValue(separator)
+	if !ok && separator != nil {
+		return nil
+	}
+
+	var b strings.Builder
+	count := 0
+	for _, a := range accessors {
+		if value, ok := stringFunctionValue(a); ok {
+			if count > 0 {
+				b.WriteString(s)
+			}
+			b.WriteString(value)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	return newString(false, b.String())
+}
+
+func Trim(accessor StringAccessor) StringAccessor {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil
+	}
+	return newString(false, strings.TrimSpace(value))
+}
+
+func Encode(accessor StringAccessor, format string) (StringAccessor, error) {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil, nil
+	}
+
+	switch format {
+	case "hex":
+		return newString(false, hex.EncodeToString([]byte(value))), nil
+	case "base64":
+		return newString(false, base64.StdEncoding.EncodeToString([]byte(value))), nil
+	case "urlbase64":
+		return newString(false, base64.URLEncoding.EncodeToString([]byte(value))), nil
+	}
+	return nil, fmt.Errorf("not a supported encoding: %s", format)
+}
+
+func Decode(accessor StringAccessor, format string) (StringAccessor, error) {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil, nil
+	}
+
+	var decoded []byte
+	var err error
+	switch format {
+	case "hex":
+		decoded, err = hex.DecodeString(value)
+	case "base64":
+		decoded, err = base64.StdEncoding.DecodeString(value)
+	case "urlbase64":
+		decoded, err = base64.URLEncoding.DecodeString(value)
+	default:
+		return nil, fmt.Errorf("not a supported encoding: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("not a valid %s encoded value: %s", format, value)
+	}
+	if !utf8.Valid(decoded) {
+		return nil, fmt.Errorf("decoded %s value is not valid UTF-8: %s", format, value)
+	}
+	return ParseString(string(decoded))
+}
+
+func Escape(accessor StringAccessor, target string) (StringAccessor, error) {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil, nil
+	}
+
+	switch target {
+	case "html":
+		return newString(false, html.EscapeString(value)), nil
+	case "json":
+		return newString(false, escapeJSONString(value)), nil
+	}
+	return nil, fmt.Errorf("not a supported escape target: %s", target)
+}
+
+func Unescape(accessor StringAccessor, target string) (StringAccessor, error) {
+	value, ok := stringFunctionValue(accessor)
+	if !ok {
+		return nil, nil
+	}
+
+	switch target {
+	case "html":
+		return ParseString(html.UnescapeString(value))
+	case "json":
+		var unescaped string
+		if err := json.Unmarshal([]byte("\""+value+"\""), &unescaped); err != nil {
+			return nil, fmt.Errorf("not a valid JSON escaped value: %s", value)
+		}
+		return ParseString(unescaped)
+	}
+	return nil, fmt.Errorf("not a supported escape target: %s", target)
+}
+
+func escapeJSONString(value string) string {
+	var b strings.Builder
+	b.Grow(len(value) + 8)
+	for _, c := range value {
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case '\b':
+			b.WriteString("\\b")
+		case '\f':
+			b.WriteString("\\f")
+		case '\n':
+			b.WriteString("\\n")
+		case '\r':
+			b.WriteString("\\r")
+		case '\t':
+			b.WriteString("\\t")
+		default:
+			if c < 0x20 {
+				b.WriteString("\\u")
+				writeStringBuilderHex(&b, int(c), 4)
+			} else {
+				b.WriteRune(c)
+			}
+		}
+	}
+	return b.String()
+}
+
+func stringFunctionValue(accessor StringAccessor) (string, bool) {
+	if accessor == nil || accessor.Nil() || !IsString(accessor) {
+		return "", false
+	}
+	return accessor.String(), true
+}
+
+func codePointIndex(value string, index int) int32 {
+	if index < 0 {
+		return -1
+	}
+	return int32(utf8.RuneCountInString(value[:index]))
+}
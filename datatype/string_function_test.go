@@ -0,0 +1,330 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func stringValues(accessors []StringAccessor) []string {
+	result := make([]string, len(accessors))
+	for i, a := range accessors {
+		result[i] = a.String()
+	}
+	return result
+}
+
+func TestIndexOf(t *testing.T) {
+	assert.Equal(t, int32(2), IndexOf(NewString("abcdefg"), NewString("cd")).Int())
+	assert.Equal(t, int32(0), IndexOf(NewString("abcdefg"), NewString("")).Int())
+	assert.Equal(t, int32(-1), IndexOf(NewString("abc"), NewString("x")).Int())
+	assert.Equal(t, int32(2), IndexOf(NewString("äöü"), NewString("ü")).Int())
+}
+
+func TestLastIndexOf(t *testing.T) {
+	assert.Equal(t, int32(4), LastIndexOf(NewString("ääcdäc"), NewString("ä")).Int())
+	assert.Equal(t, int32(-1), LastIndexOf(NewString("abc"), NewString("x")).Int())
+	assert.Equal(t, int32(0), LastIndexOf(NewString("abc"), NewString("")).Int())
+}
+
+func TestIndexOfEmpty(t *testing.T) {
+	assert.Nil(t, IndexOf(nil, NewString("a")))
+	assert.Nil(t, IndexOf(NewStringNil(), NewString("a")))
+	assert.Nil(t, IndexOf(NewString("a"), nil))
+	assert.Nil(t, LastIndexOf(NewString("a"), NewStringNil()))
+}
+
+func TestIndexOfNotString(t *testing.T) {
+	assert.Nil(t, IndexOf(NewInteger(10), NewString("1")))
+}
+
+func TestSubstring(t *testing.T) {
+	assert.Equal(t, "cdefg", Substring(NewString("abcdefg"), NewInteger(2), nil).String())
+	assert.Equal(t, "cd", Substring(NewString("abcdefg"), NewInteger(2), NewInteger(2)).String())
+	assert.Equal(t, "g", Substring(NewString("abcdefg"), NewInteger(6), NewInteger(2)).String())
+	assert.Equal(t, "", Substring(NewString("abcdefg"), NewInteger(6), NewInteger(-1)).String())
+	assert.Equal(t, "öü", Substring(NewString("äöü"), NewInteger(1), NewInteger(5)).String())
+	assert.Nil(t, Substring(NewString("abcdefg"), NewInteger(7), nil))
+	assert.Nil(t, Substring(NewString("abcdefg"), NewInteger(-1), nil))
+	assert.Nil(t, Substring(NewString("abcdefg"), nil, nil))
+	assert.Nil(t, Substring(nil, NewInteger(0), nil))
+}
+
+func TestStartsEndsWithContains(t *testing.T) {
+	assert.True(t, StartsWith(NewString("abcdefg"), NewString("abc")).Bool())
+	assert.False(t, StartsWith(NewString("abcdefg"), NewString("xyz")).Bool())
+	assert.True(t, EndsWith(NewString("abcdefg"), NewString("efg")).Bool())
+	assert.False(t, EndsWith(NewString("abcdefg"), NewString("abc")).Bool())
+	assert.True(t, Contains(NewString("abcdefg"), NewString("cde")).Bool())
+	assert.True(t, Contains(NewString("abcdefg"), NewString("")).Bool())
+	assert.False(t, Contains(NewString("abcdefg"), NewString("x")).Bool())
+	assert.Nil(t, StartsWith(nil, NewString("a")))
+	assert.Nil(t, EndsWith(NewString("a"), nil))
+	assert.Nil(t, Contains(NewStringNil(), NewString("a")))
+}
+
+func TestUpperLower(t *testing.T) {
+	assert.Equal(t, "ABCÄ", Upper(NewString("abcä")).String())
+	assert.Equal(t, "abcä", Lower(NewString("ABCÄ")).String())
+	assert.Nil(t, Upper(nil))
+	assert.Nil(t, Lower(NewStringNil()))
+}
+
+func TestReplace(t *testing.T) {
+	assert.Equal(t, "abc123efg", Replace(NewString("abcdefg"), NewString("d"), NewString("123")).String())
+	assert.Equal(t, "xaxbxcx", Replace(NewString("abc"), NewString(""), NewString("x")).String())
+	assert.Nil(t, Replace(NewString("abc"), nil, NewString("x")))
+}
+
+func TestMatches(t *testing.T) {
+	r, err := Matches(NewString("http://fhir.org/guides/cqf/common/Library/FHIR-ModelInfo|4.0.1"), NewString("Library"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.True(t, r.Bool())
+	}
+	r, err = MatchesFull(NewString("http://fhir.org/guides/cqf/common/Library/FHIR-ModelInfo|4.0.1"), NewString("Library"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.False(t, r.Bool())
+	}
+	r, err = MatchesFull(NewString("a\nb"), NewString("a.b"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.True(t, r.Bool())
+	}
+}
+
+func TestMatchesInvalidRegexp(t *testing.T) {
+	r, err := Matches(NewString("abc"), NewString("(a"))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+}
+
+func TestMatchesCachedRegexp(t *testing.T) {
+	re1, err := compileStringFunctionRegexp("a.c")
+	if assert.NoError(t, err) {
+		re2, err := compileStringFunctionRegexp("a.c")
+		assert.NoError(t, err)
+		assert.Same(t, re1, re2)
+	}
+}
+
+func TestMatchesEmpty(t *testing.T) {
+	r, err := Matches(nil, NewString("(a"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestReplaceMatches(t *testing.T) {
+	r, err := ReplaceMatches(NewString("11/30/1972"),
+		NewString("\\b(?P<month>\\d{1,2})/(?P<day>\\d{1,2})/(?P<year>\\d{2,4})\\b"),
+		NewString("${day}-${month}-${year}"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "30-11-1972", r.String())
+	}
+}
+
+func TestReplaceMatchesUncheckedValue(t *testing.T) {
+	r, err := ReplaceMatches(NewStringUnchecked("a\x01b"), NewString("b"), NewString("c"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "a\x01c", r.String())
+	}
+}
+
+func TestReplaceMatchesInvalid(t *testing.T) {
+	r, err := ReplaceMatches(NewString("abc"), NewString("(a"), NewString("x"))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+	r, err = ReplaceMatches(NewString("abc"), nil, NewString("x"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestLength(t *testing.T) {
+	assert.Equal(t, int32(3), Length(NewString("äöü")).Int())
+	assert.Equal(t, int32(0), Length(NewString("")).Int())
+	assert.Nil(t, Length(nil))
+}
+
+func TestToChars(t *testing.T) {
+	assert.Equal(t, []string{"a", "ö", "c"}, stringValues(ToChars(NewString("aöc"))))
+	assert.Empty(t, ToChars(NewString("")))
+	assert.Nil(t, ToChars(nil))
+}
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, []string{"A", "B", "C"}, stringValues(Split(NewString("A,B,C"), NewString(","))))
+	assert.Equal(t, []string{"ABC"}, stringValues(Split(NewString("ABC"), NewString(","))))
+	assert.Nil(t, Split(nil, NewString(",")))
+}
+
+func TestJoin(t *testing.T) {
+	values := []StringAccessor{NewString("A"), nil, NewCode("B"), NewString("C")}
+	assert.Equal(t, "A,B,C", Join(values, NewString(",")).String())
+	assert.Equal(t, "ABC", Join(values, nil).String())
+	assert.Nil(t, Join(values, NewStringNil()))
+	assert.Nil(t, Join(nil, NewString(",")))
+	assert.Nil(t, Join([]StringAccessor{NewStringNil()}, NewString(",")))
+}
+
+func TestTrim(t *testing.T) {
+	assert.Equal(t, "abc", Trim(NewString(" \tabc\r\n")).String())
+	assert.Nil(t, Trim(nil))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		format  string
+		value   string
+		encoded string
+	}{
+		{"hex", "test?ä", "746573743fc3a4"},
+		{"base64", "test?ä", "dGVzdD/DpA=="},
+		{"urlbase64", "test?ä", "dGVzdD_DpA=="},
+	}
+	for _, test := range tests {
+		r, err := Encode(NewString(test.value), test.format)
+		if assert.NoError(t, err) && assert.NotNil(t, r) {
+			assert.Equal(t, test.encoded, r.String(), "unexpected encoding for %s", test.format)
+		}
+		r, err = Decode(NewString(test.encoded), test.format)
+		if assert.NoError(t, err) && assert.NotNil(t, r) {
+			assert.Equal(t, test.value, r.String(), "unexpected decoding for %s", test.format)
+		}
+	}
+}
+
+func TestEncodeDecodeUnsupported(t *testing.T) {
+	_, err := Encode(NewString("a"), "rot13")
+	assert.Error(t, err, "error expected")
+	_, err = Decode(NewString("a"), "rot13")
+	assert.Error(t, err, "error expected")
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	_, err := Decode(NewString("zz"), "hex")
+	assert.Error(t, err, "error expected")
+	_, err = Decode(NewString("ff"), "hex")
+	assert.Error(t, err, "error expected")
+	_, err = Decode(NewString("01"), "hex")
+	assert.Error(t, err, "error expected")
+}
+
+func TestEncodeDecodeEmpty(t *testing.T) {
+	r, err := Encode(nil, "hex")
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	r, err = Decode(NewStringNil(), "hex")
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestEscapeUnescape(t *testing.T) {
+	tests := []struct {
+		target  string
+		value   string
+		escaped string
+	}{
+		{"html", "\"1<2\" & 'x'", "&#34;1&lt;2&#34; &amp; &#39;x&#39;"},
+		{"json", "\"a\\b\"\n\tä", "\\\"a\\\\b\\\"\\n\\tä"},
+	}
+	for _, test := range tests {
+		r, err := Escape(NewString(test.value), test.target)
+		if assert.NoError(t, err) && assert.NotNil(t, r) {
+			assert.Equal(t, test.escaped, r.String(), "unexpected escaping for %s", test.target)
+		}
+		r, err = Unescape(NewString(test.escaped), test.target)
+		if assert.NoError(t, err) && assert.NotNil(t, r) {
+			assert.Equal(t, test.value, r.String(), "unexpected unescaping for %s", test.target)
+		}
+	}
+}
+
+func TestUnescapeJSONUnicode(t *testing.T) {
+	r, err := Unescape(NewString("\\u00e4x"), "json")
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "äx", r.String())
+	}
+}
+
+func TestUnescapeInvalid(t *testing.T) {
+	_, err := Unescape(NewString("a\"b"), "json")
+	assert.Error(t, err, "error expected")
+	_, err = Unescape(NewString("\\u0001"), "json")
+	assert.Error(t, err, "error expected")
+}
+
+func TestEscapeUnsupported(t *testing.T) {
+	_, err := Escape(NewString("a"), "xml")
+	assert.Error(t, err, "error expected")
+	_, err = Unescape(NewString("a"), "xml")
+	assert.Error(t, err, "error expected")
+}
+
+func TestEscapeEmpty(t *testing.T) {
+	r, err := Escape(nil, "html")
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	r, err = Unescape(nil, "html")
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestStringFunctionsUncheckedValue(t *testing.T) {
+	value := NewStringUnchecked("a\x01b\x01c")
+	assert.Equal(t, "a\x01b", Substring(value, NewInteger(0), NewInteger(3)).String())
+	assert.Equal(t, "A\x01B\x01C", Upper(value).String())
+	assert.Equal(t, "a\x01b\x01c", Lower(value).String())
+	assert.Equal(t, "a\x01B\x01c", Replace(value, NewString("b"), NewString("B")).String())
+	assert.Equal(t, []string{"a", "\x01", "b", "\x01", "c"}, stringValues(ToChars(value)))
+	assert.Equal(t, []string{"a", "b", "c"}, stringValues(Split(value, NewStringUnchecked("\x01"))))
+	assert.Equal(t, "a\x01b\x01c\x01a\x01b\x01c", Join([]StringAccessor{value, value}, NewStringUnchecked("\x01")).String())
+	assert.Equal(t, "a\x01b\x01c", Trim(value).String())
+	r, err := Encode(value, "hex")
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "6101620163", r.String())
+	}
+	r, err = Escape(value, "html")
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "a\x01b\x01c", r.String())
+	}
+	r, err = Escape(value, "json")
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "a\\u0001b\\u0001c", r.String())
+	}
+}
+
+func TestStringFunctionsOnStringTypes(t *testing.T) {
+	assert.Equal(t, int32(4), Length(NewCode("test")).Int())
+	assert.Equal(t, "TEST", Upper(NewID("test")).String())
+	assert.True(t, StartsWith(NewMarkdown("# Title"), NewString("#")).Bool())
+	assert.Equal(t, StringDataType, Upper(NewCode("test")).DataType())
+}
+
+func TestStringFunctionsNotStringType(t *testing.T) {
+	assert.Nil(t, Length(NewURI("http://example.com")))
+}
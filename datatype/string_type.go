@@ -28,14 +28,10 @@
 
 package datatype
 
-import (
-	"fmt"
-	"regexp"
-)
+import "unicode/utf8"
 
-var stringTypeSpec = newElementTypeSpec("string")
-
-var stringRegexp = regexp.MustCompile("^[\\r\\n\\t\\x{0020}-\\x{FFFF}]*$")
+var stringTypeSpec = newElementTypeSpec("string").SetProperties(
+	newPrimitiveValueProperty(systemStringTypeSpec))
 
 type stringType struct {
 	PrimitiveType
@@ -59,8 +55,8 @@ func NewStringNil() StringAccessor {
 }
 
 func NewString(value string) StringAccessor {
-	if !stringRegexp.MatchString(value) {
-		panic(fmt.Sprintf("not a valid string: %s", value))
+	if pos := stringErrorPosition(value); pos >= 0 {
+		panic(newSyntaxParseError(StringDataType, value, pos))
 	}
 	return newString(false, value)
 }
@@ -70,12 +66,33 @@ func NewStringUnchecked(value string) StringAccessor {
 }
 
 func ParseString(value string) (StringAccessor, error) {
-	if !stringRegexp.MatchString(value) {
-		return nil, fmt.Errorf("not a valid string: %s", value)
+	if pos := stringErrorPosition(value); pos >= 0 {
+		return nil, newSyntaxParseError(StringDataType, value, pos)
 	}
 	return newString(false, value), nil
 }
 
+func stringErrorPosition(value string) int {
+	for pos := 0; pos < len(value); {
+		c, size := utf8.DecodeRuneInString(value[pos:])
+		if !validStringRune(c, size) {
+			return pos
+		}
+		pos += size
+	}
+	return -1
+}
+
+func validStringRune(c rune, size int) bool {
+	if c == utf8.RuneError && size <= 1 {
+		return false
+	}
+	if c < 0x20 {
+		return c == '\r' || c == '\n' || c == '\t'
+	}
+	return c != 0xFFFE && c != 0xFFFF
+}
+
 func newString(nilValue bool, value string) StringAccessor {
 	return &stringType{
 		PrimitiveType: PrimitiveType{
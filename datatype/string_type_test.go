@@ -136,3 +136,48 @@ func TestStringEqualNotEqual(t *testing.T) {
 	assert.Equal(t, false, NewString("test1").Equal(NewString("test2")))
 	assert.Equal(t, false, NewString("test1").Equivalent(NewString("test2")))
 }
+
+func TestParseStringInvalidPosition(t *testing.T) {
+	_, err := ParseString("Test\u0005String")
+	assertParseError(t, err, StringDataType, 4, InvalidSyntaxParseErrorReason)
+}
+
+func TestStringSupplementaryPlane(t *testing.T) {
+	for _, value := range []string{"Smile \U0001F600", "\U00020000\U0002A6D6", "\uFDD0\U0001FFFE", "\u007F\u0085"} {
+		s, err := ParseString(value)
+		if assert.NoError(t, err, "no error expected for %q", value) {
+			assert.Equal(t, value, s.String())
+		}
+		assert.NotPanics(t, func() { NewString(value) })
+	}
+}
+
+func TestParseStringInvalidCharacters(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+	}{
+		{"ab\xed\xa0\x80", 2},
+		{"ab\xed\xb0\x80c", 2},
+		{"\U0001F600\xff", 4},
+		{"a\uFFFE", 1},
+		{"a\uFFFF", 1},
+		{"a\x00", 1},
+		{"\U0001F600\x1b", 4},
+	}
+	for _, test := range tests {
+		_, err := ParseString(test.value)
+		assertParseError(t, err, StringDataType, test.position, InvalidSyntaxParseErrorReason)
+		assert.Panics(t, func() { NewString(test.value) })
+	}
+}
+
+func TestStringErrorPositionValid(t *testing.T) {
+	assert.Equal(t, -1, stringErrorPosition("Test\r\n\t\U0001F600\uFFFD"))
+}
+
+func TestStringEquivalentDiacritics(t *testing.T) {
+	assert.Equal(t, true, NewString("Jos\u00E9").Equivalent(NewString("jose")))
+	assert.Equal(t, true, NewCode("Jos\u00E9").Equivalent(NewString("jose")))
+	assert.Equal(t, false, NewString("Jos\u00E9").Equal(NewString("jose")))
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+var systemAnyTypeSpec = newSystemTypeSpec("Any", nil)
+var systemBooleanTypeSpec = newSystemTypeSpec("Boolean", systemAnyTypeSpec)
+var systemStringTypeSpec = newSystemTypeSpec("String", systemAnyTypeSpec)
+var systemIntegerTypeSpec = newSystemTypeSpec("Integer", systemAnyTypeSpec)
+var systemDecimalTypeSpec = newSystemTypeSpec("Decimal", systemAnyTypeSpec)
+var systemDateTypeSpec = newSystemTypeSpec("Date", systemAnyTypeSpec)
+var systemDateTimeTypeSpec = newSystemTypeSpec("DateTime", systemAnyTypeSpec)
+var systemTimeTypeSpec = newSystemTypeSpec("Time", systemAnyTypeSpec)
+var systemQuantityTypeSpec = newSystemTypeSpec("Quantity", systemAnyTypeSpec)
+
+var systemTypeSpecsByFHIRName = map[string]TypeSpecAccessor{
+	"boolean":      systemBooleanTypeSpec,
+	"string":       systemStringTypeSpec,
+	"code":         systemStringTypeSpec,
+	"id":           systemStringTypeSpec,
+	"markdown":     systemStringTypeSpec,
+	"uri":          systemStringTypeSpec,
+	"url":          systemStringTypeSpec,
+	"canonical":    systemStringTypeSpec,
+	"oid":          systemStringTypeSpec,
+	"uuid":         systemStringTypeSpec,
+	"base64Binary": systemStringTypeSpec,
+	"integer":      systemIntegerTypeSpec,
+	"unsignedInt":  systemIntegerTypeSpec,
+	"positiveInt":  systemIntegerTypeSpec,
+	"decimal":      systemDecimalTypeSpec,
+	"date":         systemDateTypeSpec,
+	"dateTime":     systemDateTimeTypeSpec,
+	"instant":      systemDateTimeTypeSpec,
+	"time":         systemTimeTypeSpec,
+	"Quantity":     systemQuantityTypeSpec,
+}
+
+var fhirTypeSpecsBySystemName = map[string]TypeSpecAccessor{
+	"Boolean":  booleanTypeSpec,
+	"String":   stringTypeSpec,
+	"Integer":  integerTypeSpec,
+	"Decimal":  decimalTypeSpec,
+	"Date":     dateTypeSpec,
+	"DateTime": dateTimeTypeSpec,
+	"Time":     timeTypeSpec,
+	"Quantity": quantityTypeSpec,
+}
+
+type systemValue interface {
+	fhirValue() Accessor
+}
+
+type systemBooleanType struct {
+	BooleanAccessor
+}
+
+type systemStringType struct {
+	StringAccessor
+}
+
+type systemIntegerType struct {
+	IntegerAccessor
+}
+
+type systemDecimalType struct {
+	DecimalAccessor
+}
+
+type systemDateType struct {
+	DateAccessor
+}
+
+type systemDateTimeType struct {
+	DateTimeAccessor
+}
+
+type systemTimeType struct {
+	TimeAccessor
+}
+
+type systemQuantityType struct {
+	QuantityAccessor
+}
+
+func newSystemTypeSpec(name string, base TypeSpecAccessor) *TypeSpec {
+	return NewTypeSpecWithBase(NewFQTypeName(name, SystemNamespaceName), base)
+}
+
+func IsSystemTypeSpec(spec TypeSpecAccessor) bool {
+	return spec != nil && spec.FQName() != nil && spec.FQName().Namespace() == SystemNamespaceName
+}
+
+func SystemTypeSpecOf(spec TypeSpecAccessor) TypeSpecAccessor {
+	if IsSystemTypeSpec(spec) {
+		return spec
+	}
+	for t := spec; t != nil; t = t.Base() {
+		if name := t.FQName(); name != nil && name.Namespace() == FHIRNamespaceName {
+			if systemSpec, found := systemTypeSpecsByFHIRName[name.Name()]; found {
+				return systemSpec
+			}
+		}
+	}
+	return nil
+}
+
+func FHIRTypeSpecOf(spec TypeSpecAccessor) TypeSpecAccessor {
+	if spec == nil || spec.FQName() == nil {
+		return nil
+	}
+	if spec.FQName().Namespace() == FHIRNamespaceName {
+		return spec
+	}
+	if !IsSystemTypeSpec(spec) {
+		return nil
+	}
+	return fhirTypeSpecsBySystemName[spec.FQName().Name()]
+}
+
+func SystemValueOf(accessor Accessor) Accessor {
+	if Empty(accessor) {
+		return nil
+	}
+	if _, ok := accessor.(systemValue); ok {
+		return accessor
+	}
+
+	switch SystemTypeSpecOf(accessor.TypeSpec()) {
+	case systemBooleanTypeSpec:
+		if a, ok := accessor.(BooleanAccessor); ok {
+			return &systemBooleanType{a}
+		}
+	case systemStringTypeSpec:
+		if a, ok := accessor.(StringAccessor); ok {
+			if a.DataType() != StringDataType {
+				a = NewStringUnchecked(a.String())
+			}
+			return &systemStringType{a}
+		}
+	case systemIntegerTypeSpec:
+		if a, ok := accessor.(IntegerAccessor); ok {
+			if a.DataType() != IntegerDataType {
+				a = NewInteger(a.Int())
+			}
+			return &systemIntegerType{a}
+		}
+	case systemDecimalTypeSpec:
+		if a, ok := accessor.(DecimalAccessor); ok {
+			return &systemDecimalType{a}
+		}
+	case systemDateTypeSpec:
+		if a, ok := accessor.(DateAccessor); ok {
+			return &systemDateType{a}
+		}
+	case systemDateTimeTypeSpec:
+		if a, ok := accessor.(DateTimeAccessor); ok {
+			return &systemDateTimeType{a}
+		}
+	case systemTimeTypeSpec:
+		if a, ok := accessor.(TimeAccessor); ok {
+			return &systemTimeType{a}
+		}
+	case systemQuantityTypeSpec:
+		if a, ok := accessor.(QuantityAccessor); ok {
+			return &systemQuantityType{a}
+		}
+	}
+	return nil
+}
+
+func FHIRValueOf(accessor Accessor) Accessor {
+	if a, ok := accessor.(systemValue); ok {
+		return a.fhirValue()
+	}
+	return accessor
+}
+
+func (t *systemBooleanType) TypeSpec() TypeSpecAccessor {
+	return systemBooleanTypeSpec
+}
+
+func (t *systemBooleanType) fhirValue() Accessor {
+	return t.BooleanAccessor
+}
+
+func (t *systemStringType) TypeSpec() TypeSpecAccessor {
+	return systemStringTypeSpec
+}
+
+func (t *systemStringType) fhirValue() Accessor {
+	return t.StringAccessor
+}
+
+func (t *systemIntegerType) TypeSpec() TypeSpecAccessor {
+	return systemIntegerTypeSpec
+}
+
+func (t *systemIntegerType) fhirValue() Accessor {
+	return t.IntegerAccessor
+}
+
+func (t *systemDecimalType) TypeSpec() TypeSpecAccessor {
+	return systemDecimalTypeSpec
+}
+
+func (t *systemDecimalType) fhirValue() Accessor {
+	return t.DecimalAccessor
+}
+
+func (t *systemDateType) TypeSpec() TypeSpecAccessor {
+	return systemDateTypeSpec
+}
+
+func (t *systemDateType) fhirValue() Accessor {
+	return t.DateAccessor
+}
+
+func (t *systemDateTimeType) TypeSpec() TypeSpecAccessor {
+	return systemDateTimeTypeSpec
+}
+
+func (t *systemDateTimeType) fhirValue() Accessor {
+	return t.DateTimeAccessor
+}
+
+func (t *systemTimeType) TypeSpec() TypeSpecAccessor {
+	return systemTimeTypeSpec
+}
+
+func (t *systemTimeType) fhirValue() Accessor {
+	return t.TimeAccessor
+}
+
+func (t *systemQuantityType) TypeSpec() TypeSpecAccessor {
+	return systemQuantityTypeSpec
+}
+
+func (t *systemQuantityType) fhirValue() Accessor {
+	return t.QuantityAccessor
+}
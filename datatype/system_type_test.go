@@ -0,0 +1,198 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSystemTypeSpecs(t *testing.T) {
+	for _, name := range []string{"Boolean", "String", "Integer", "Decimal", "Date", "DateTime", "Time", "Quantity"} {
+		spec := LookupTypeSpec("System." + name)
+		if assert.NotNil(t, spec, "type spec expected for %s", name) {
+			assert.Equal(t, SystemNamespaceName, spec.FQName().Namespace())
+			assert.Equal(t, "System.Any", spec.FQBaseName().String())
+			assert.True(t, IsSystemTypeSpec(spec))
+		}
+	}
+	assert.Nil(t, LookupTypeSpec("System.Any").Base())
+}
+
+func TestLookupTypeSpecSystemName(t *testing.T) {
+	assert.Same(t, systemStringTypeSpec, LookupTypeSpec("String"))
+	assert.Same(t, stringTypeSpec, LookupTypeSpec("string"))
+	assert.Same(t, quantityTypeSpec, LookupTypeSpec("Quantity"))
+}
+
+func TestIsSystemTypeSpecNot(t *testing.T) {
+	assert.False(t, IsSystemTypeSpec(stringTypeSpec))
+	assert.False(t, IsSystemTypeSpec(NewTypeSpec(nil)))
+	assert.False(t, IsSystemTypeSpec(nil))
+}
+
+func TestSystemTypeSpecOf(t *testing.T) {
+	assert.Same(t, systemBooleanTypeSpec, SystemTypeSpecOf(booleanTypeSpec))
+	assert.Same(t, systemStringTypeSpec, SystemTypeSpecOf(stringTypeSpec))
+	assert.Same(t, systemStringTypeSpec, SystemTypeSpecOf(codeTypeSpec))
+	assert.Same(t, systemStringTypeSpec, SystemTypeSpecOf(uriTypeSpec))
+	assert.Same(t, systemIntegerTypeSpec, SystemTypeSpecOf(positiveIntTypeSpec))
+	assert.Same(t, systemDecimalTypeSpec, SystemTypeSpecOf(decimalTypeSpec))
+	assert.Same(t, systemDateTypeSpec, SystemTypeSpecOf(dateTypeSpec))
+	assert.Same(t, systemDateTimeTypeSpec, SystemTypeSpecOf(dateTimeTypeSpec))
+	assert.Same(t, systemTimeTypeSpec, SystemTypeSpecOf(timeTypeSpec))
+	assert.Same(t, systemQuantityTypeSpec, SystemTypeSpecOf(quantityTypeSpec))
+	assert.Same(t, systemDateTypeSpec, SystemTypeSpecOf(systemDateTypeSpec))
+}
+
+func TestSystemTypeSpecOfDerived(t *testing.T) {
+	age := NewTypeSpecWithBase(NewFQTypeName("Age", FHIRNamespaceName), quantityTypeSpec)
+	assert.Same(t, systemQuantityTypeSpec, SystemTypeSpecOf(age))
+}
+
+func TestSystemTypeSpecOfNone(t *testing.T) {
+	assert.Nil(t, SystemTypeSpecOf(elementTypeSpec))
+	assert.Nil(t, SystemTypeSpecOf(NewTypeSpec(NewFQTypeName("string", "Other"))))
+	assert.Nil(t, SystemTypeSpecOf(nil))
+}
+
+func TestFHIRTypeSpecOf(t *testing.T) {
+	assert.Same(t, booleanTypeSpec, FHIRTypeSpecOf(systemBooleanTypeSpec))
+	assert.Same(t, stringTypeSpec, FHIRTypeSpecOf(systemStringTypeSpec))
+	assert.Same(t, integerTypeSpec, FHIRTypeSpecOf(systemIntegerTypeSpec))
+	assert.Same(t, decimalTypeSpec, FHIRTypeSpecOf(systemDecimalTypeSpec))
+	assert.Same(t, dateTypeSpec, FHIRTypeSpecOf(systemDateTypeSpec))
+	assert.Same(t, dateTimeTypeSpec, FHIRTypeSpecOf(systemDateTimeTypeSpec))
+	assert.Same(t, timeTypeSpec, FHIRTypeSpecOf(systemTimeTypeSpec))
+	assert.Same(t, quantityTypeSpec, FHIRTypeSpecOf(systemQuantityTypeSpec))
+	assert.Same(t, codeTypeSpec, FHIRTypeSpecOf(codeTypeSpec))
+}
+
+func TestFHIRTypeSpecOfNone(t *testing.T) {
+	assert.Nil(t, FHIRTypeSpecOf(systemAnyTypeSpec))
+	assert.Nil(t, FHIRTypeSpecOf(NewTypeSpec(NewFQTypeName("String", "Other"))))
+	assert.Nil(t, FHIRTypeSpecOf(NewTypeSpec(nil)))
+	assert.Nil(t, FHIRTypeSpecOf(nil))
+}
+
+func TestSystemValueOfBoolean(t *testing.T) {
+	v := SystemValueOf(NewBoolean(true))
+	if assert.Implements(t, (*BooleanAccessor)(nil), v) {
+		assert.Equal(t, "System.Boolean", v.TypeSpec().String())
+		assert.Equal(t, BooleanDataType, v.DataType())
+		assert.True(t, v.(BooleanAccessor).Bool())
+		assert.True(t, v.Equal(NewBoolean(true)))
+		assert.True(t, NewBoolean(true).Equal(v))
+	}
+}
+
+func TestSystemValueOfString(t *testing.T) {
+	v := SystemValueOf(NewString("test"))
+	assert.Equal(t, "System.String", v.TypeSpec().String())
+	assert.Equal(t, StringDataType, v.DataType())
+	assert.Equal(t, "test", v.(StringAccessor).String())
+	assert.True(t, v.Equivalent(NewString("TEST")))
+}
+
+func TestSystemValueOfCode(t *testing.T) {
+	v := SystemValueOf(NewCode("test"))
+	assert.Equal(t, "System.String", v.TypeSpec().String())
+	assert.Equal(t, StringDataType, v.DataType())
+	assert.Equal(t, "FHIR.string", FHIRValueOf(v).TypeSpec().String())
+}
+
+func TestSystemValueOfURI(t *testing.T) {
+	v := SystemValueOf(NewURI("urn:test"))
+	assert.Equal(t, "System.String", v.TypeSpec().String())
+	assert.Equal(t, "urn:test", v.(StringAccessor).String())
+}
+
+func TestSystemValueOfInteger(t *testing.T) {
+	v := SystemValueOf(NewPositiveInt(10))
+	if assert.Implements(t, (*IntegerAccessor)(nil), v) {
+		assert.Equal(t, "System.Integer", v.TypeSpec().String())
+		assert.Equal(t, IntegerDataType, v.DataType())
+		assert.Equal(t, int32(10), v.(IntegerAccessor).Int())
+	}
+}
+
+func TestSystemValueOfDecimal(t *testing.T) {
+	v := SystemValueOf(NewDecimalInt(10))
+	assert.Implements(t, (*DecimalAccessor)(nil), v)
+	assert.Equal(t, "System.Decimal", v.TypeSpec().String())
+}
+
+func TestSystemValueOfTemporal(t *testing.T) {
+	date := SystemValueOf(NewDateYMD(2020, 2, 29))
+	assert.Implements(t, (*DateAccessor)(nil), date)
+	assert.Equal(t, "System.Date", date.TypeSpec().String())
+
+	dateTime := SystemValueOf(mustParseDateTime(t, "2020-02-29T10:20:30Z"))
+	assert.Implements(t, (*DateTimeAccessor)(nil), dateTime)
+	assert.Equal(t, "System.DateTime", dateTime.TypeSpec().String())
+
+	tm := SystemValueOf(NewTimeHMSN(10, 20, 30, 0))
+	assert.Implements(t, (*TimeAccessor)(nil), tm)
+	assert.Equal(t, "System.Time", tm.TypeSpec().String())
+}
+
+func TestSystemValueOfQuantity(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(10), nil, nil, UCUMSystemURI, NewCode("mg"))
+	v := SystemValueOf(q)
+	assert.Implements(t, (*QuantityAccessor)(nil), v)
+	assert.Equal(t, "System.Quantity", v.TypeSpec().String())
+	assert.True(t, q.Equal(v))
+	assert.Same(t, q, FHIRValueOf(v))
+}
+
+func TestSystemValueOfSystemValue(t *testing.T) {
+	v := SystemValueOf(NewString("test"))
+	assert.Same(t, v, SystemValueOf(v))
+}
+
+func TestSystemValueOfEmpty(t *testing.T) {
+	assert.Nil(t, SystemValueOf(nil))
+	assert.Nil(t, SystemValueOf(NewStringNil()))
+	assert.Nil(t, SystemValueOf(NewQuantityEmpty()))
+}
+
+func TestFHIRValueOf(t *testing.T) {
+	s := NewString("test")
+	assert.Same(t, s, FHIRValueOf(SystemValueOf(s)))
+	assert.Same(t, s, FHIRValueOf(s))
+	assert.Nil(t, FHIRValueOf(nil))
+}
+
+func TestSystemValueIsSubtypeOf(t *testing.T) {
+	v := SystemValueOf(NewString("test"))
+	assert.True(t, IsSubtypeOf(v.TypeSpec(), LookupTypeSpec("System.String")))
+	assert.True(t, IsSubtypeOf(v.TypeSpec(), LookupTypeSpec("System.Any")))
+	assert.False(t, IsSubtypeOf(v.TypeSpec(), LookupTypeSpec("FHIR.string")))
+	assert.False(t, IsSubtypeOf(NewString("test").TypeSpec(), LookupTypeSpec("System.String")))
+}
@@ -66,6 +66,8 @@ type DateTemporalAccessor interface {
 	Year() int
 	Month() int
 	Day() int
+	Add(quantity QuantityAccessor) (DateTemporalAccessor, error)
+	Subtract(quantity QuantityAccessor) (DateTemporalAccessor, error)
 }
 
 func (t *TemporalType) Precision() DateTimePrecisions {
@@ -0,0 +1,239 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const nanosPerSecond = int64(time.Second)
+
+type temporalDurationUnit struct {
+	precision DateTimePrecisions
+	factor    int64
+}
+
+var temporalDurationUnits = map[string]temporalDurationUnit{
+	"a":   {YearDatePrecision, 1},
+	"mo":  {MonthDatePrecision, 1},
+	"wk":  {DayDatePrecision, 7},
+	"d":   {DayDatePrecision, 1},
+	"h":   {HourTimePrecision, 1},
+	"min": {MinuteTimePrecision, 1},
+	"s":   {NanoTimePrecision, nanosPerSecond},
+	"ms":  {NanoTimePrecision, int64(time.Millisecond)},
+}
+
+var temporalPrecisionFactors = [...]int64{
+	MonthDatePrecision:  12,
+	DayDatePrecision:    30,
+	HourTimePrecision:   24,
+	MinuteTimePrecision: 60,
+	SecondTimePrecision: 60,
+	NanoTimePrecision:   nanosPerSecond,
+}
+
+var timeOfDayPeriods = [...]int64{
+	HourTimePrecision:   24,
+	MinuteTimePrecision: 24 * 60,
+	SecondTimePrecision: 24 * 60 * 60,
+	NanoTimePrecision:   24 * 60 * 60 * nanosPerSecond,
+}
+
+var timeOfDayNanos = [...]int64{
+	HourTimePrecision:   60 * 60 * nanosPerSecond,
+	MinuteTimePrecision: 60 * nanosPerSecond,
+	SecondTimePrecision: nanosPerSecond,
+	NanoTimePrecision:   1,
+}
+
+func temporalDuration(quantity QuantityAccessor, precision DateTimePrecisions, negate bool) (int64, DateTimePrecisions, bool, error) {
+	if quantity == nil || quantity.Value() == nil || quantity.Value().Nil() {
+		return 0, 0, false, nil
+	}
+
+	code := StringValue(quantity.Code())
+	if _, ok := calendarDurationUnit(quantity); !ok {
+		if system := quantity.System(); system != nil && !system.Nil() && system.String() != UCUMSystemURI.String() {
+			return 0, 0, false, fmt.Errorf("not a time-valued quantity: %s", FormatQuantityLiteral(quantity))
+		}
+		if code == "a" || code == "mo" {
+			return 0, 0, false, fmt.Errorf("not a definite duration unit for date/time arithmetic: %s", code)
+		}
+	}
+	unit, found := temporalDurationUnits[code]
+	if !found {
+		return 0, 0, false, fmt.Errorf("not a time-valued quantity: %s", FormatQuantityLiteral(quantity))
+	}
+
+	value := quantity.Value().Decimal().Mul(decimal.NewFromInt(unit.factor)).Truncate(0)
+	if !value.BigInt().IsInt64() {
+		return 0, 0, false, nil
+	}
+	amount := value.IntPart()
+	if negate {
+		amount = -amount
+	}
+
+	p := unit.precision
+	for p > precision {
+		amount = amount / temporalPrecisionFactors[p]
+		p--
+	}
+	return amount, p, true, nil
+}
+
+func addDateTemporal(value time.Time, amount int64, precision DateTimePrecisions) (time.Time, bool) {
+	switch precision {
+	case YearDatePrecision:
+		return addMonths(value, amount*12)
+	case MonthDatePrecision:
+		return addMonths(value, amount)
+	case DayDatePrecision:
+		return addDays(value, amount)
+	}
+
+	period := timeOfDayPeriods[precision]
+	result, ok := addDays(value, amount/period)
+	if !ok {
+		return result, false
+	}
+	result = result.Add(time.Duration((amount % period) * timeOfDayNanos[precision]))
+	return result, validTemporalYear(result.Year())
+}
+
+func addMonths(value time.Time, months int64) (time.Time, bool) {
+	if months > 12*10000 || months < -12*10000 {
+		return value, false
+	}
+
+	m := int64(value.Month()) - 1 + months
+	year := value.Year() + int(m/12)
+	m = m % 12
+	if m < 0 {
+		m = m + 12
+		year = year - 1
+	}
+	month := time.Month(m + 1)
+
+	day := value.Day()
+	if last := daysInMonth(year, month); day > last {
+		day = last
+	}
+	return time.Date(year, month, day, value.Hour(), value.Minute(), value.Second(),
+		value.Nanosecond(), value.Location()), validTemporalYear(year)
+}
+
+func addDays(value time.Time, days int64) (time.Time, bool) {
+	if days > 366*10000 || days < -366*10000 {
+		return value, false
+	}
+	result := value.AddDate(0, 0, int(days))
+	return result, validTemporalYear(result.Year())
+}
+
+var monthDays = [...]int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+func daysInMonth(year int, month time.Month) int {
+	if month == time.February && isLeapYear(year) {
+		return 29
+	}
+	return monthDays[month-1]
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+func validTemporalYear(year int) bool {
+	return year >= 1 && year <= 9999
+}
+
+func addDate(accessor DateAccessor, quantity QuantityAccessor, negate bool) (DateTemporalAccessor, error) {
+	if accessor.Nil() {
+		return nil, nil
+	}
+	amount, precision, ok, err := temporalDuration(quantity, accessor.Precision(), negate)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	value := time.Date(accessor.Year(), time.Month(accessor.Month()), accessor.Day(), 0, 0, 0, 0, time.UTC)
+	result, ok := addDateTemporal(value, amount, precision)
+	if !ok {
+		return nil, nil
+	}
+	return NewDateYMDWithPrecision(result.Year(), int(result.Month()), result.Day(), accessor.Precision()), nil
+}
+
+func addDateTime(accessor DateTimeAccessor, quantity QuantityAccessor, negate bool) (DateTemporalAccessor, error) {
+	if accessor.Nil() {
+		return nil, nil
+	}
+	amount, precision, ok, err := temporalDuration(quantity, accessor.Precision(), negate)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	result, ok := addDateTemporal(accessor.Time(), amount, precision)
+	if !ok {
+		return nil, nil
+	}
+	return newDateTimeWithPrecision(result, accessor.Precision(), accessor.HasTimeZone()), nil
+}
+
+func addTime(accessor TimeAccessor, quantity QuantityAccessor, negate bool) (TimeAccessor, error) {
+	if accessor.Nil() {
+		return nil, nil
+	}
+	amount, precision, ok, err := temporalDuration(quantity, accessor.Precision(), negate)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if precision < HourTimePrecision {
+		return nil, fmt.Errorf("not a time-valued quantity for time arithmetic: %s", FormatQuantityLiteral(quantity))
+	}
+
+	day := timeOfDayPeriods[NanoTimePrecision]
+	nanos := int64(accessor.Hour())*timeOfDayNanos[HourTimePrecision] +
+		int64(accessor.Minute())*timeOfDayNanos[MinuteTimePrecision] +
+		int64(accessor.Second())*timeOfDayNanos[SecondTimePrecision] +
+		int64(accessor.Nanosecond())
+	nanos = (nanos + (amount%timeOfDayPeriods[precision])*timeOfDayNanos[precision]) % day
+	if nanos < 0 {
+		nanos = nanos + day
+	}
+
+	return NewTimeHMSNWithPrecision(int(nanos/timeOfDayNanos[HourTimePrecision]),
+		int(nanos%timeOfDayNanos[HourTimePrecision]/timeOfDayNanos[MinuteTimePrecision]),
+		int(nanos%timeOfDayNanos[MinuteTimePrecision]/timeOfDayNanos[SecondTimePrecision]),
+		int(nanos%timeOfDayNanos[SecondTimePrecision]), accessor.Precision()), nil
+}
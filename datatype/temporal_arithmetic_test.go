@@ -0,0 +1,233 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func mustParseQuantityLiteral(t *testing.T, value string) QuantityAccessor {
+	q, err := ParseQuantityLiteral(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestDateAdd(t *testing.T) {
+	tests := []struct {
+		value    string
+		quantity string
+		expected string
+	}{
+		{"2014", "24 months", "2016"},
+		{"2014", "1 year", "2015"},
+		{"2014", "400 days", "2015"},
+		{"2014-01", "45 days", "2014-02"},
+		{"2014-01", "29 days", "2014-01"},
+		{"2019-03-01", "24 hours", "2019-03-02"},
+		{"2019-03-01", "23 hours", "2019-03-01"},
+		{"2021-01-31", "1 month", "2021-02-28"},
+		{"2020-01-31", "1 month", "2020-02-29"},
+		{"2020-02-29", "1 year", "2021-02-28"},
+		{"2020-12-31", "2 days", "2021-01-02"},
+		{"2020-12-31", "2 'wk'", "2021-01-14"},
+		{"2020-12-31", "1.9 'd'", "2021-01-01"},
+	}
+	for _, test := range tests {
+		r, err := mustParseDate(t, test.value).Add(mustParseQuantityLiteral(t, test.quantity))
+		if assert.NoError(t, err, "no error expected for %s + %s", test.value, test.quantity) &&
+			assert.NotNil(t, r, "result expected for %s + %s", test.value, test.quantity) {
+			assert.Equal(t, DateDataType, r.DataType())
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s + %s", test.value, test.quantity)
+		}
+	}
+}
+
+func TestDateSubtract(t *testing.T) {
+	tests := []struct {
+		value    string
+		quantity string
+		expected string
+	}{
+		{"2014", "2 years", "2012"},
+		{"2021-03-31", "1 month", "2021-02-28"},
+		{"2021-01-01", "1 day", "2020-12-31"},
+		{"2021-01", "13 months", "2019-12"},
+	}
+	for _, test := range tests {
+		r, err := mustParseDate(t, test.value).Subtract(mustParseQuantityLiteral(t, test.quantity))
+		if assert.NoError(t, err, "no error expected for %s - %s", test.value, test.quantity) &&
+			assert.NotNil(t, r, "result expected for %s - %s", test.value, test.quantity) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s - %s", test.value, test.quantity)
+		}
+	}
+}
+
+func TestDateAddEmpty(t *testing.T) {
+	r, err := NewDateNil().Add(mustParseQuantityLiteral(t, "1 day"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	r, err = NewDateYMD(2020, 1, 1).Add(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	r, err = NewDateYMD(2020, 1, 1).Add(NewQuantity(NewDecimalNil(), nil, nil, UCUMSystemURI, NewCode("d")))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestDateAddOutOfRange(t *testing.T) {
+	r, err := NewDateYMD(9999, 12, 31).Add(mustParseQuantityLiteral(t, "1 day"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	r, err = NewDateYMD(1, 1, 1).Subtract(mustParseQuantityLiteral(t, "1 month"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	r, err = NewDateYMD(2020, 1, 1).Add(mustParseQuantityLiteral(t, "100000000000000000000 days"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestDateAddInvalidUnit(t *testing.T) {
+	for _, literal := range []string{"1 'a'", "1 'mo'", "1 'mg'"} {
+		r, err := NewDateYMD(2020, 1, 1).Add(mustParseQuantityLiteral(t, literal))
+		assert.Error(t, err, "error expected for %s", literal)
+		assert.Nil(t, r)
+	}
+	r, err := NewDateYMD(2020, 1, 1).Add(NewQuantity(NewDecimalInt(1), nil, nil, NewURI("urn:test"), NewCode("d")))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+}
+
+func TestDateTimeAdd(t *testing.T) {
+	tests := []struct {
+		value    string
+		quantity string
+		expected string
+	}{
+		{"2021-01-31T10:00:00Z", "1 month", "2021-02-28T10:00:00Z"},
+		{"2021-01-31T10:00:00+02:00", "1 'mo'", ""},
+		{"2021-01-31T23:30:00Z", "45 minutes", "2021-02-01T00:15:00Z"},
+		{"2021-01-31T23:30:00Z", "2 'h'", "2021-02-01T01:30:00Z"},
+		{"2021-01-31T23:30:00Z", "1.5 seconds", "2021-01-31T23:30:01Z"},
+		{"2021-01-31T23:30:00.000Z", "1.5 seconds", "2021-01-31T23:30:01.500000000Z"},
+		{"2021-01-31T23:30:00.000Z", "250 'ms'", "2021-01-31T23:30:00.250000000Z"},
+		{"2021-01-31T23:30:00Z", "100000 hours", "2032-06-29T15:30:00Z"},
+		{"2021-01", "1 'wk'", "2021-01"},
+		{"2021-01", "1 year", "2022-01"},
+	}
+	for _, test := range tests {
+		r, err := mustParseDateTime(t, test.value).Add(mustParseQuantityLiteral(t, test.quantity))
+		if test.expected == "" {
+			assert.Error(t, err, "error expected for %s + %s", test.value, test.quantity)
+		} else if assert.NoError(t, err, "no error expected for %s + %s", test.value, test.quantity) &&
+			assert.NotNil(t, r, "result expected for %s + %s", test.value, test.quantity) {
+			assert.Equal(t, DateTimeDataType, r.DataType())
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s + %s", test.value, test.quantity)
+		}
+	}
+}
+
+func TestDateTimeSubtract(t *testing.T) {
+	r, err := mustParseDateTime(t, "2021-03-01T00:30:00+01:00").Subtract(mustParseQuantityLiteral(t, "1 hour"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "2021-02-28T23:30:00+01:00", r.String())
+	}
+}
+
+func TestDateTimeAddEmpty(t *testing.T) {
+	r, err := NewDateTimeNil().Add(mustParseQuantityLiteral(t, "1 day"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	r, err = NewDateTime(time.Date(9999, 12, 31, 23, 0, 0, 0, time.UTC)).Add(mustParseQuantityLiteral(t, "1 hour"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestTimeAdd(t *testing.T) {
+	tests := []struct {
+		value    string
+		quantity string
+		expected string
+	}{
+		{"10:00:00", "1 hour", "11:00:00"},
+		{"23:30:00", "45 minutes", "00:15:00"},
+		{"23:30:00", "49 hours", "00:30:00"},
+		{"10:00:00", "1.5 seconds", "10:00:01"},
+		{"10:00:00.000", "1.5 seconds", "10:00:01.500000000"},
+		{"10:00:00.000", "-250 'ms'", "09:59:59.750000000"},
+	}
+	for _, test := range tests {
+		r, err := mustParseTime(t, test.value).Add(mustParseQuantityLiteral(t, test.quantity))
+		if assert.NoError(t, err, "no error expected for %s + %s", test.value, test.quantity) &&
+			assert.NotNil(t, r, "result expected for %s + %s", test.value, test.quantity) {
+			assert.Equal(t, TimeDataType, r.DataType())
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s + %s", test.value, test.quantity)
+		}
+	}
+}
+
+func TestTimeAddHourPrecision(t *testing.T) {
+	r, err := NewTimeHMSNWithPrecision(10, 0, 0, 0, HourTimePrecision).Add(mustParseQuantityLiteral(t, "90 minutes"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "11", r.String())
+	}
+}
+
+func TestTimeSubtract(t *testing.T) {
+	r, err := mustParseTime(t, "00:15:00").Subtract(mustParseQuantityLiteral(t, "30 minutes"))
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "23:45:00", r.String())
+	}
+}
+
+func TestTimeAddDateUnit(t *testing.T) {
+	for _, literal := range []string{"1 day", "1 'wk'", "1 month", "1 year"} {
+		r, err := mustParseTime(t, "10:00:00").Add(mustParseQuantityLiteral(t, literal))
+		assert.Error(t, err, "error expected for %s", literal)
+		assert.Nil(t, r)
+	}
+}
+
+func TestTimeAddEmpty(t *testing.T) {
+	r, err := NewTimeNil().Add(mustParseQuantityLiteral(t, "1 hour"))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestDaysInMonth(t *testing.T) {
+	assert.Equal(t, 31, daysInMonth(2021, time.January))
+	assert.Equal(t, 28, daysInMonth(2021, time.February))
+	assert.Equal(t, 29, daysInMonth(2020, time.February))
+	assert.Equal(t, 28, daysInMonth(1900, time.February))
+	assert.Equal(t, 29, daysInMonth(2000, time.February))
+	assert.Equal(t, 31, daysInMonth(2020, time.December))
+}
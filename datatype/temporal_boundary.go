@@ -0,0 +1,131 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"time"
+)
+
+const maxTimeZoneOffset = 14 * 60 * 60
+
+var earliestTimeZone = time.FixedZone(fmt.Sprint(maxTimeZoneOffset), maxTimeZoneOffset)
+var latestTimeZone = time.FixedZone(fmt.Sprint(-maxTimeZoneOffset), -maxTimeZoneOffset)
+
+func (t *dateType) LowBoundary(precision DateTimePrecisions) DateAccessor {
+	if t.Nil() {
+		return nil
+	}
+	return NewDateYMDWithPrecision(t.year, t.month, t.day, precision)
+}
+
+func (t *dateType) HighBoundary(precision DateTimePrecisions) DateAccessor {
+	if t.Nil() {
+		return nil
+	}
+
+	month, day := t.month, t.day
+	if t.precision < MonthDatePrecision {
+		month = 12
+	}
+	if t.precision < DayDatePrecision {
+		day = daysInMonth(t.year, time.Month(month))
+	}
+	return NewDateYMDWithPrecision(t.year, month, day, precision)
+}
+
+func (t *dateTimeType) LowBoundary(precision DateTimePrecisions) DateTimeAccessor {
+	if t.Nil() {
+		return nil
+	}
+
+	location := t.value.Location()
+	if !t.timeZone && precision >= HourTimePrecision {
+		location = earliestTimeZone
+	}
+	v := t.value
+	return NewDateTimeWithPrecision(time.Date(v.Year(), v.Month(), v.Day(),
+		v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), location), precision)
+}
+
+func (t *dateTimeType) HighBoundary(precision DateTimePrecisions) DateTimeAccessor {
+	if t.Nil() {
+		return nil
+	}
+
+	location := t.value.Location()
+	if !t.timeZone && precision >= HourTimePrecision {
+		location = latestTimeZone
+	}
+	v := t.value
+	year, month, day := v.Year(), v.Month(), v.Day()
+	if t.precision < MonthDatePrecision {
+		month = time.December
+	}
+	if t.precision < DayDatePrecision {
+		day = daysInMonth(year, month)
+	}
+	hour, minute, second, nanosecond := highTimeBoundary(t.precision,
+		v.Hour(), v.Minute(), v.Second(), v.Nanosecond())
+	return NewDateTimeWithPrecision(time.Date(year, month, day,
+		hour, minute, second, nanosecond, location), precision)
+}
+
+func (t *timeType) LowBoundary(precision DateTimePrecisions) TimeAccessor {
+	if t.Nil() {
+		return nil
+	}
+	return NewTimeHMSNWithPrecision(t.hour, t.minute, t.second, t.nanosecond, precision)
+}
+
+func (t *timeType) HighBoundary(precision DateTimePrecisions) TimeAccessor {
+	if t.Nil() {
+		return nil
+	}
+
+	hour, minute, second, nanosecond := highTimeBoundary(t.precision,
+		t.hour, t.minute, t.second, t.nanosecond)
+	return NewTimeHMSNWithPrecision(hour, minute, second, nanosecond, precision)
+}
+
+func highTimeBoundary(precision DateTimePrecisions, hour int, minute int, second int, nanosecond int) (int, int, int, int) {
+	if precision < HourTimePrecision {
+		hour = 23
+	}
+	if precision < MinuteTimePrecision {
+		minute = 59
+	}
+	if precision < SecondTimePrecision {
+		second = 59
+	}
+	if precision < NanoTimePrecision {
+		nanosecond = int(time.Second - 1)
+	}
+	return hour, minute, second, nanosecond
+}
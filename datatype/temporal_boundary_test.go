@@ -0,0 +1,209 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDateLowBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision DateTimePrecisions
+		expected  string
+	}{
+		{"2014", DayDatePrecision, "2014-01-01"},
+		{"2014", MonthDatePrecision, "2014-01"},
+		{"2014-05", DayDatePrecision, "2014-05-01"},
+		{"2014-05-17", YearDatePrecision, "2014"},
+		{"2014-05-17", NanoTimePrecision, "2014-05-17"},
+	}
+	for _, test := range tests {
+		r := mustParseDate(t, test.value).LowBoundary(test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s", test.value)
+		}
+	}
+}
+
+func TestDateHighBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision DateTimePrecisions
+		expected  string
+	}{
+		{"2014", DayDatePrecision, "2014-12-31"},
+		{"2014", MonthDatePrecision, "2014-12"},
+		{"2014-02", DayDatePrecision, "2014-02-28"},
+		{"2016-02", DayDatePrecision, "2016-02-29"},
+		{"1900-02", DayDatePrecision, "1900-02-28"},
+		{"2000-02", DayDatePrecision, "2000-02-29"},
+		{"2014-04", DayDatePrecision, "2014-04-30"},
+		{"2014-05-17", YearDatePrecision, "2014"},
+	}
+	for _, test := range tests {
+		r := mustParseDate(t, test.value).HighBoundary(test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s", test.value)
+		}
+	}
+}
+
+func TestDateBoundaryNil(t *testing.T) {
+	assert.Nil(t, NewDateNil().LowBoundary(DayDatePrecision))
+	assert.Nil(t, NewDateNil().HighBoundary(DayDatePrecision))
+}
+
+func TestDateTimeLowBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision DateTimePrecisions
+		expected  string
+	}{
+		{"2014", DayDatePrecision, "2014-01-01"},
+		{"2014-05", NanoTimePrecision, "2014-05-01T00:00:00.000000000+14:00"},
+		{"2014-05-17", MinuteTimePrecision, "2014-05-17T00:00+14:00"},
+		{"2014-05-17T08:10:20+02:00", NanoTimePrecision, "2014-05-17T08:10:20.000000000+02:00"},
+		{"2014-05-17T08:12:45.123Z", SecondTimePrecision, "2014-05-17T08:12:45Z"},
+		{"2014-05-17T08:12:45Z", YearDatePrecision, "2014"},
+	}
+	for _, test := range tests {
+		r := mustParseDateTime(t, test.value).LowBoundary(test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s", test.value)
+		}
+	}
+}
+
+func TestDateTimeHighBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision DateTimePrecisions
+		expected  string
+	}{
+		{"2014", DayDatePrecision, "2014-12-31"},
+		{"2016-02", DayDatePrecision, "2016-02-29"},
+		{"2014-05", NanoTimePrecision, "2014-05-31T23:59:59.999999999-14:00"},
+		{"2014-05-17", MinuteTimePrecision, "2014-05-17T23:59-14:00"},
+		{"2014-05-17T08:10:20+02:00", NanoTimePrecision, "2014-05-17T08:10:20.999999999+02:00"},
+		{"2014-05-17T08:12:45Z", NanoTimePrecision, "2014-05-17T08:12:45.999999999Z"},
+		{"2014-05-17T08:12:45Z", MonthDatePrecision, "2014-05"},
+	}
+	for _, test := range tests {
+		r := mustParseDateTime(t, test.value).HighBoundary(test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s", test.value)
+		}
+	}
+}
+
+func TestDateTimeBoundaryOrder(t *testing.T) {
+	dt := mustParseDateTime(t, "2014-05-17")
+	low := dt.LowBoundary(NanoTimePrecision)
+	high := dt.HighBoundary(NanoTimePrecision)
+	assert.Equal(t, "2014-05-16T10:00:00Z", low.Time().UTC().Format("2006-01-02T15:04:05Z"))
+	assert.Equal(t, "2014-05-18T13:59:59Z", high.Time().UTC().Format("2006-01-02T15:04:05Z"))
+}
+
+func TestDateTimeBoundaryWithoutTimeZone(t *testing.T) {
+	dt := newDateTimeWithPrecision(time.Date(2014, 5, 17, 8, 10, 20, 0, time.UTC), SecondTimePrecision, false)
+	assert.Equal(t, "2014-05-17T08:10:20.000000000+14:00", dt.LowBoundary(NanoTimePrecision).String())
+	assert.Equal(t, "2014-05-17T08:10:20.999999999-14:00", dt.HighBoundary(NanoTimePrecision).String())
+	assert.Equal(t, "2014-05-17", dt.LowBoundary(DayDatePrecision).String())
+}
+
+func TestDateTimeBoundaryHourPrecisionWithoutTimeZone(t *testing.T) {
+	dt := newDateTimeWithPrecision(time.Date(2014, 5, 17, 8, 0, 0, 0, time.UTC), HourTimePrecision, false)
+	assert.Equal(t, "2014-05-17T08:00+14:00", dt.LowBoundary(MinuteTimePrecision).String())
+	assert.Equal(t, "2014-05-17T08:59-14:00", dt.HighBoundary(MinuteTimePrecision).String())
+}
+
+func TestDateTimeBoundaryHourPrecisionWithTimeZone(t *testing.T) {
+	dt := NewDateTimeWithPrecision(time.Date(2014, 5, 17, 8, 0, 0, 0, time.UTC), HourTimePrecision)
+	assert.Equal(t, "2014-05-17T08:00Z", dt.LowBoundary(MinuteTimePrecision).String())
+	assert.Equal(t, "2014-05-17T08:59Z", dt.HighBoundary(MinuteTimePrecision).String())
+}
+
+func TestDateTimeBoundaryNil(t *testing.T) {
+	assert.Nil(t, NewDateTimeNil().LowBoundary(DayDatePrecision))
+	assert.Nil(t, NewDateTimeNil().HighBoundary(DayDatePrecision))
+}
+
+func TestTimeLowBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision DateTimePrecisions
+		expected  string
+	}{
+		{"10:30:15.5", SecondTimePrecision, "10:30:15"},
+		{"10:30:15", HourTimePrecision, "10"},
+	}
+	for _, test := range tests {
+		r := mustParseTime(t, test.value).LowBoundary(test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s", test.value)
+		}
+	}
+}
+
+func TestTimeHighBoundary(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision DateTimePrecisions
+		expected  string
+	}{
+		{"10:30:15", NanoTimePrecision, "10:30:15.999999999"},
+		{"10:30:15", MinuteTimePrecision, "10:30"},
+	}
+	for _, test := range tests {
+		r := mustParseTime(t, test.value).HighBoundary(test.precision)
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s", test.value)
+		}
+	}
+}
+
+func TestTimeBoundaryHourPrecision(t *testing.T) {
+	tm := NewTimeHMSNWithPrecision(10, 0, 0, 0, HourTimePrecision)
+	assert.Equal(t, "10:00:00.000000000", tm.LowBoundary(NanoTimePrecision).String())
+	assert.Equal(t, "10:59:59.999999999", tm.HighBoundary(NanoTimePrecision).String())
+}
+
+func TestDateTimeBoundaryHourPrecision(t *testing.T) {
+	dt := NewDateTimeWithPrecision(mustParseDateTime(t, "2014-05-17T08:00:00+02:00").Time(), HourTimePrecision)
+	assert.Equal(t, "2014-05-17T08:00:00.000000000+02:00", dt.LowBoundary(NanoTimePrecision).String())
+	assert.Equal(t, "2014-05-17T08:59:59.999999999+02:00", dt.HighBoundary(NanoTimePrecision).String())
+}
+
+func TestTimeBoundaryNil(t *testing.T) {
+	assert.Nil(t, NewTimeNil().LowBoundary(HourTimePrecision))
+	assert.Nil(t, NewTimeNil().HighBoundary(HourTimePrecision))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+const temporalComponentCount = 6
+
+func CompareTemporal(a TemporalAccessor, b TemporalAccessor) ComparisonResults {
+	if a == nil || b == nil || a.Nil() || b.Nil() {
+		return EmptyComparisonResult
+	}
+	if (a.DataType() == TimeDataType) != (b.DataType() == TimeDataType) {
+		return EmptyComparisonResult
+	}
+
+	pa, pb := comparisonPrecision(a), comparisonPrecision(b)
+	normalize := pa >= HourTimePrecision && pb >= HourTimePrecision
+	va, vb := temporalComponentValues(a, normalize), temporalComponentValues(b, normalize)
+
+	p := pa
+	if pb < p {
+		p = pb
+	}
+	for i := a.LowestPrecision(); i <= p; i++ {
+		if va[i] < vb[i] {
+			return LessComparisonResult
+		}
+		if va[i] > vb[i] {
+			return GreaterComparisonResult
+		}
+	}
+
+	if pa != pb {
+		return EmptyComparisonResult
+	}
+	return EqualComparisonResult
+}
+
+func comparisonPrecision(accessor TemporalAccessor) DateTimePrecisions {
+	if p := accessor.Precision(); p < SecondTimePrecision {
+		return p
+	}
+	return SecondTimePrecision
+}
+
+func temporalComponentValues(accessor TemporalAccessor, normalize bool) [temporalComponentCount]int64 {
+	var v [temporalComponentCount]int64
+	switch a := accessor.(type) {
+	case DateTimeAccessor:
+		t := a.Time()
+		if normalize {
+			t = t.UTC()
+		}
+		v[YearDatePrecision], v[MonthDatePrecision], v[DayDatePrecision] =
+			int64(t.Year()), int64(t.Month()), int64(t.Day())
+		v[HourTimePrecision], v[MinuteTimePrecision], v[SecondTimePrecision] =
+			int64(t.Hour()), int64(t.Minute()), int64(t.Second())*1e9+int64(t.Nanosecond())
+	case DateTemporalAccessor:
+		v[YearDatePrecision], v[MonthDatePrecision], v[DayDatePrecision] =
+			int64(a.Year()), int64(a.Month()), int64(a.Day())
+	case TimeAccessor:
+		v[HourTimePrecision], v[MinuteTimePrecision], v[SecondTimePrecision] =
+			int64(a.Hour()), int64(a.Minute()), int64(a.Second())*1e9+int64(a.Nanosecond())
+	}
+	return v
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func mustParseDateTime(t *testing.T, value string) DateTimeAccessor {
+	dt, err := ParseDateTime(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dt
+}
+
+func mustParseDate(t *testing.T, value string) DateAccessor {
+	d, err := ParseDate(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func mustParseTime(t *testing.T, value string) TimeAccessor {
+	tm, err := ParseTime(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestCompareTemporalEmpty(t *testing.T) {
+	assert.Equal(t, EmptyComparisonResult, CompareTemporal(nil, NewDateYMD(2020, 1, 1)))
+	assert.Equal(t, EmptyComparisonResult, CompareTemporal(NewDateYMD(2020, 1, 1), nil))
+	assert.Equal(t, EmptyComparisonResult, CompareTemporal(NewDateNil(), NewDateYMD(2020, 1, 1)))
+	assert.Equal(t, EmptyComparisonResult, CompareTemporal(NewTimeHMSN(10, 0, 0, 0), NewTimeNil()))
+}
+
+func TestCompareTemporalTimeAndDate(t *testing.T) {
+	assert.Equal(t, EmptyComparisonResult, CompareTemporal(NewTimeHMSN(10, 0, 0, 0), NewDateYMD(2020, 1, 1)))
+	assert.Equal(t, EmptyComparisonResult, CompareTemporal(NewDateTime(time.Now()), NewTimeHMSN(10, 0, 0, 0)))
+}
+
+func TestCompareTemporalDate(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected ComparisonResults
+	}{
+		{"2012", "2013", LessComparisonResult},
+		{"2013", "2012", GreaterComparisonResult},
+		{"2012", "2012", EqualComparisonResult},
+		{"2012", "2012-01-01", EmptyComparisonResult},
+		{"2012-01", "2013-01-01", LessComparisonResult},
+		{"2012-02", "2012-01-31", GreaterComparisonResult},
+		{"2012-01-01", "2012-01-01", EqualComparisonResult},
+		{"2012-01-01", "2012-01-02", LessComparisonResult},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, CompareTemporal(mustParseDate(t, test.a), mustParseDate(t, test.b)),
+			"unexpected result for %s and %s", test.a, test.b)
+	}
+}
+
+func TestCompareTemporalDateTime(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected ComparisonResults
+	}{
+		{"2012-01-01T10:30:00Z", "2012-01-01T10:30:00Z", EqualComparisonResult},
+		{"2012-01-01T10:30:31.0Z", "2012-01-01T10:30:31Z", EqualComparisonResult},
+		{"2012-01-01T10:30:31.1Z", "2012-01-01T10:30:31Z", GreaterComparisonResult},
+		{"2012-01-01T10:30:00Z", "2012-01-01T11:30:00+01:00", EqualComparisonResult},
+		{"2012-01-01T00:30:00+01:00", "2011-12-31T23:31:00Z", LessComparisonResult},
+		{"2012-01-01", "2012-01-01T10:30:00Z", EmptyComparisonResult},
+		{"2012-01-02", "2012-01-01T10:30:00Z", GreaterComparisonResult},
+		{"2012", "2013-01-01T10:30:00Z", LessComparisonResult},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, CompareTemporal(mustParseDateTime(t, test.a), mustParseDateTime(t, test.b)),
+			"unexpected result for %s and %s", test.a, test.b)
+	}
+}
+
+func TestCompareTemporalDateAndDateTime(t *testing.T) {
+	assert.Equal(t, EmptyComparisonResult,
+		CompareTemporal(mustParseDate(t, "2012-01-01"), mustParseDateTime(t, "2012-01-01T10:30:00Z")))
+	assert.Equal(t, EqualComparisonResult,
+		CompareTemporal(mustParseDate(t, "2012-01"), mustParseDateTime(t, "2012-01")))
+	assert.Equal(t, LessComparisonResult,
+		CompareTemporal(mustParseDate(t, "2012-01-01"), mustParseDateTime(t, "2012-01-02T10:30:00Z")))
+}
+
+func TestCompareTemporalTime(t *testing.T) {
+	tests := []struct {
+		a        TimeAccessor
+		b        TimeAccessor
+		expected ComparisonResults
+	}{
+		{mustParseTime(t, "10:30:00"), mustParseTime(t, "10:30:00"), EqualComparisonResult},
+		{mustParseTime(t, "10:30:00.000"), mustParseTime(t, "10:30:00"), EqualComparisonResult},
+		{mustParseTime(t, "10:30:00.5"), mustParseTime(t, "10:30:01"), LessComparisonResult},
+		{mustParseTime(t, "11:00:00"), mustParseTime(t, "10:59:59"), GreaterComparisonResult},
+		{NewTimeHMSNWithPrecision(10, 0, 0, 0, HourTimePrecision), mustParseTime(t, "10:30:00"), EmptyComparisonResult},
+		{NewTimeHMSNWithPrecision(10, 0, 0, 0, HourTimePrecision), mustParseTime(t, "11:30:00"), LessComparisonResult},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, CompareTemporal(test.a, test.b),
+			"unexpected result for %s and %s", test.a, test.b)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+type fixedClock struct {
+	value time.Time
+}
+
+type TemporalContext struct {
+	location *time.Location
+	clock    Clock
+}
+
+var defaultTemporalContext = newDefaultTemporalContext()
+var defaultTemporalContextLock sync.Mutex
+
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+func NewFixedClock(value time.Time) Clock {
+	return &fixedClock{value: value}
+}
+
+func (c systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.value
+}
+
+func NewTemporalContext(location *time.Location, clock Clock) *TemporalContext {
+	if location == nil {
+		location = time.Local
+	}
+	if clock == nil {
+		clock = SystemClock()
+	}
+	return &TemporalContext{
+		location: location,
+		clock:    clock,
+	}
+}
+
+func newDefaultTemporalContext() *atomic.Value {
+	var v atomic.Value
+	v.Store(NewTemporalContext(nil, nil))
+	return &v
+}
+
+func DefaultTemporalContext() *TemporalContext {
+	return defaultTemporalContext.Load().(*TemporalContext)
+}
+
+func SetDefaultTemporalContext(context *TemporalContext) *TemporalContext {
+	if context == nil {
+		context = NewTemporalContext(nil, nil)
+	}
+
+	defaultTemporalContextLock.Lock()
+	defer defaultTemporalContextLock.Unlock()
+	previous := DefaultTemporalContext()
+	defaultTemporalContext.Store(context)
+	return previous
+}
+
+func (c *TemporalContext) Location() *time.Location {
+	return c.location
+}
+
+func (c *TemporalContext) Clock() Clock {
+	return c.clock
+}
+
+func (c *TemporalContext) ParseDateTime(value string) (DateTimeAccessor, error) {
+	return parseDateTime(value, c.location)
+}
+
+func (c *TemporalContext) DateTimeFromDate(accessor DateAccessor) DateTimeAccessor {
+	return dateTimeFromDate(accessor, c.location)
+}
+
+func (c *TemporalContext) CombineDateTime(date DateAccessor, tm TimeAccessor) DateTimeAccessor {
+	return combineDateTime(date, tm, c.location, false)
+}
+
+func (c *TemporalContext) Now() DateTimeAccessor {
+	return NewDateTime(c.clock.Now().In(c.location))
+}
+
+func (c *TemporalContext) Today() DateAccessor {
+	now := c.clock.Now().In(c.location)
+	return NewDateYMD(now.Year(), int(now.Month()), now.Day())
+}
+
+func (c *TemporalContext) TimeOfDay() TimeAccessor {
+	now := c.clock.Now().In(c.location)
+	return NewTimeHMSN(now.Hour(), now.Minute(), now.Second(), now.Nanosecond())
+}
+
+func Now() DateTimeAccessor {
+	return DefaultTemporalContext().Now()
+}
+
+func Today() DateAccessor {
+	return DefaultTemporalContext().Today()
+}
+
+func TimeOfDay() TimeAccessor {
+	return DefaultTemporalContext().TimeOfDay()
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var testClockTime = time.Date(2020, 12, 31, 22, 30, 15, 125000000, time.UTC)
+
+func TestNewTemporalContextDefaults(t *testing.T) {
+	c := NewTemporalContext(nil, nil)
+	assert.Same(t, time.Local, c.Location())
+	assert.Equal(t, SystemClock(), c.Clock())
+}
+
+func TestDefaultTemporalContext(t *testing.T) {
+	c := DefaultTemporalContext()
+	assert.Same(t, time.Local, c.Location())
+	assert.Equal(t, SystemClock(), c.Clock())
+}
+
+func TestSetDefaultTemporalContext(t *testing.T) {
+	location := time.FixedZone("+03:00", 3*60*60)
+	c := NewTemporalContext(location, NewFixedClock(testClockTime))
+	previous := SetDefaultTemporalContext(c)
+	t.Cleanup(func() { SetDefaultTemporalContext(previous) })
+
+	assert.Same(t, c, DefaultTemporalContext())
+	assert.Equal(t, "2021-01-01", Today().String())
+
+	dt, err := ParseDateTime("2020-01-02")
+	if assert.NoError(t, err) && assert.NotNil(t, dt) {
+		assert.Same(t, location, dt.Time().Location())
+	}
+	dt = DateTimeFromDate(NewDateYMD(2020, 1, 2))
+	if assert.NotNil(t, dt) {
+		assert.Same(t, location, dt.Time().Location())
+	}
+	r, err := Convert(NewDateYMD(2020, 1, 2), DateTimeDataType)
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Same(t, location, r.(DateTimeAccessor).Time().Location())
+	}
+	dt = CombineDateTime(NewDateYMD(2020, 1, 2), NewTimeHMSN(10, 30, 0, 0), nil)
+	if assert.NotNil(t, dt) {
+		assert.Same(t, location, dt.Time().Location())
+	}
+}
+
+func TestSetDefaultTemporalContextNil(t *testing.T) {
+	previous := SetDefaultTemporalContext(nil)
+	t.Cleanup(func() { SetDefaultTemporalContext(previous) })
+
+	c := DefaultTemporalContext()
+	assert.Same(t, time.Local, c.Location())
+	assert.Equal(t, SystemClock(), c.Clock())
+}
+
+func TestSystemClock(t *testing.T) {
+	before := time.Now()
+	now := SystemClock().Now()
+	assert.False(t, now.Before(before))
+}
+
+func TestFixedClock(t *testing.T) {
+	assert.Equal(t, testClockTime, NewFixedClock(testClockTime).Now())
+}
+
+func TestTemporalContextParseDateTime(t *testing.T) {
+	location := time.FixedZone("+03:00", 3*60*60)
+	c := NewTemporalContext(location, nil)
+	dt, err := c.ParseDateTime("2020-01-02")
+	if assert.NoError(t, err) && assert.NotNil(t, dt) {
+		assert.Same(t, location, dt.Time().Location())
+		assert.Equal(t, "2020-01-02", dt.String())
+	}
+}
+
+func TestTemporalContextParseDateTimeZone(t *testing.T) {
+	c := NewTemporalContext(time.FixedZone("+03:00", 3*60*60), nil)
+	dt, err := c.ParseDateTime("2020-01-02T10:00:00Z")
+	if assert.NoError(t, err) && assert.NotNil(t, dt) {
+		assert.Same(t, time.UTC, dt.Time().Location())
+	}
+}
+
+func TestTemporalContextParseDateTimeInvalid(t *testing.T) {
+	c := NewTemporalContext(time.UTC, nil)
+	dt, err := c.ParseDateTime("2020-01-02T")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, dt)
+}
+
+func TestTemporalContextDateTimeFromDate(t *testing.T) {
+	c := NewTemporalContext(time.UTC, nil)
+	dt := c.DateTimeFromDate(NewDateYMD(2020, 1, 2))
+	if assert.NotNil(t, dt) {
+		assert.Same(t, time.UTC, dt.Time().Location())
+		assert.Equal(t, "2020-01-02", dt.String())
+	}
+}
+
+func TestTemporalContextCombineDateTime(t *testing.T) {
+	location := time.FixedZone("+03:00", 3*60*60)
+	c := NewTemporalContext(location, nil)
+	dt := c.CombineDateTime(NewDateYMD(2020, 1, 2), NewTimeHMSN(10, 30, 0, 0))
+	if assert.NotNil(t, dt) {
+		assert.Same(t, location, dt.Time().Location())
+		assert.False(t, dt.HasTimeZone())
+		assert.Equal(t, NanoTimePrecision, dt.Precision())
+	}
+	dt = c.CombineDateTime(NewDateYMD(2020, 1, 2), nil)
+	if assert.NotNil(t, dt) {
+		assert.Same(t, location, dt.Time().Location())
+		assert.Equal(t, DayDatePrecision, dt.Precision())
+	}
+}
+
+func TestTemporalContextNow(t *testing.T) {
+	c := NewTemporalContext(time.FixedZone("+03:00", 3*60*60), NewFixedClock(testClockTime))
+	now := c.Now()
+	if assert.NotNil(t, now) {
+		assert.Equal(t, NanoTimePrecision, now.Precision())
+		assert.Equal(t, "2021-01-01T01:30:15.125000000+03:00", now.String())
+		assert.True(t, now.Time().Equal(testClockTime))
+	}
+}
+
+func TestTemporalContextToday(t *testing.T) {
+	c := NewTemporalContext(time.FixedZone("+03:00", 3*60*60), NewFixedClock(testClockTime))
+	assert.Equal(t, "2021-01-01", c.Today().String())
+	c = NewTemporalContext(time.UTC, NewFixedClock(testClockTime))
+	assert.Equal(t, "2020-12-31", c.Today().String())
+}
+
+func TestTemporalContextTimeOfDay(t *testing.T) {
+	c := NewTemporalContext(time.FixedZone("-02:00", -2*60*60), NewFixedClock(testClockTime))
+	assert.Equal(t, "20:30:15.125000000", c.TimeOfDay().String())
+}
+
+func TestNow(t *testing.T) {
+	before := time.Now()
+	now := Now()
+	if assert.NotNil(t, now) {
+		assert.False(t, now.Time().Before(before))
+		assert.Same(t, time.Local, now.Time().Location())
+	}
+}
+
+func TestToday(t *testing.T) {
+	now := time.Now()
+	today := Today()
+	if assert.NotNil(t, today) {
+		assert.Equal(t, DayDatePrecision, today.Precision())
+		assert.LessOrEqual(t, now.Year(), today.Year())
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	tm := TimeOfDay()
+	if assert.NotNil(t, tm) {
+		assert.Equal(t, NanoTimePrecision, tm.Precision())
+	}
+}
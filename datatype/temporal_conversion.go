@@ -0,0 +1,76 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import "time"
+
+func DateFromDateTime(accessor DateTimeAccessor) DateAccessor {
+	if accessor == nil || accessor.Nil() || !validTemporalYear(accessor.Year()) {
+		return nil
+	}
+	return NewDateYMDWithPrecision(accessor.Year(), accessor.Month(), accessor.Day(), accessor.Precision())
+}
+
+func TimeFromDateTime(accessor DateTimeAccessor) TimeAccessor {
+	if accessor == nil || accessor.Nil() || accessor.Precision() < HourTimePrecision {
+		return nil
+	}
+	return NewTimeHMSNWithPrecision(accessor.Hour(), accessor.Minute(), accessor.Second(),
+		accessor.Nanosecond(), accessor.Precision())
+}
+
+func DateTimeFromDate(accessor DateAccessor) DateTimeAccessor {
+	return DefaultTemporalContext().DateTimeFromDate(accessor)
+}
+
+func dateTimeFromDate(accessor DateAccessor, location *time.Location) DateTimeAccessor {
+	if accessor == nil || accessor.Nil() {
+		return nil
+	}
+	return NewDateTimeWithPrecision(time.Date(accessor.Year(), time.Month(accessor.Month()), accessor.Day(),
+		0, 0, 0, 0, location), accessor.Precision())
+}
+
+func CombineDateTime(date DateAccessor, tm TimeAccessor, location *time.Location) DateTimeAccessor {
+	if location == nil {
+		return DefaultTemporalContext().CombineDateTime(date, tm)
+	}
+	return combineDateTime(date, tm, location, true)
+}
+
+func combineDateTime(date DateAccessor, tm TimeAccessor, location *time.Location, timeZone bool) DateTimeAccessor {
+	if date == nil || date.Nil() {
+		return nil
+	}
+	if tm == nil || tm.Nil() || date.Precision() < DayDatePrecision {
+		return dateTimeFromDate(date, location)
+	}
+	return newDateTimeWithPrecision(time.Date(date.Year(), time.Month(date.Month()), date.Day(),
+		tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), location), tm.Precision(), timeZone)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDateFromDateTime(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"2014", "2014"},
+		{"2014-05", "2014-05"},
+		{"2014-05-17", "2014-05-17"},
+		{"2014-05-17T23:30:12.123+05:00", "2014-05-17"},
+	}
+	for _, test := range tests {
+		r := DateFromDateTime(mustParseDateTime(t, test.value))
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s", test.value)
+		}
+	}
+}
+
+func TestDateFromDateTimeNil(t *testing.T) {
+	assert.Nil(t, DateFromDateTime(nil))
+	assert.Nil(t, DateFromDateTime(NewDateTimeNil()))
+}
+
+func TestTimeFromDateTime(t *testing.T) {
+	r := TimeFromDateTime(mustParseDateTime(t, "2014-05-17T23:30:12.123+05:00"))
+	if assert.NotNil(t, r) {
+		assert.Equal(t, "23:30:12.123000000", r.String())
+		assert.Equal(t, NanoTimePrecision, r.Precision())
+	}
+	r = TimeFromDateTime(mustParseDateTime(t, "2014-05-17T23:30:12Z"))
+	if assert.NotNil(t, r) {
+		assert.Equal(t, "23:30:12", r.String())
+		assert.Equal(t, SecondTimePrecision, r.Precision())
+	}
+}
+
+func TestTimeFromDateTimeNoTime(t *testing.T) {
+	assert.Nil(t, TimeFromDateTime(mustParseDateTime(t, "2014-05-17")))
+	assert.Nil(t, TimeFromDateTime(nil))
+	assert.Nil(t, TimeFromDateTime(NewDateTimeNil()))
+}
+
+func TestDateTimeFromDate(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision DateTimePrecisions
+	}{
+		{"2014", YearDatePrecision},
+		{"2014-05", MonthDatePrecision},
+		{"2014-05-17", DayDatePrecision},
+	}
+	for _, test := range tests {
+		r := DateTimeFromDate(mustParseDate(t, test.value))
+		if assert.NotNil(t, r, "result expected for %s", test.value) {
+			assert.Equal(t, test.value, r.String())
+			assert.Equal(t, test.precision, r.Precision())
+		}
+	}
+}
+
+func TestDateTimeFromDateNil(t *testing.T) {
+	assert.Nil(t, DateTimeFromDate(nil))
+	assert.Nil(t, DateTimeFromDate(NewDateNil()))
+}
+
+func TestCombineDateTime(t *testing.T) {
+	r := CombineDateTime(mustParseDate(t, "2014-05-17"), mustParseTime(t, "23:30:12.5"),
+		time.FixedZone("+02:00", 2*60*60))
+	if assert.NotNil(t, r) {
+		assert.Equal(t, "2014-05-17T23:30:12.500000000+02:00", r.String())
+		assert.Equal(t, NanoTimePrecision, r.Precision())
+	}
+}
+
+func TestCombineDateTimeDefaultLocation(t *testing.T) {
+	previous := SetDefaultTemporalContext(NewTemporalContext(time.UTC, nil))
+	t.Cleanup(func() { SetDefaultTemporalContext(previous) })
+
+	r := CombineDateTime(mustParseDate(t, "2014-05-17"), mustParseTime(t, "23:30:12"), nil)
+	if assert.NotNil(t, r) {
+		assert.Equal(t, "2014-05-17T23:30:12Z", r.String())
+		assert.False(t, r.HasTimeZone())
+	}
+	r = CombineDateTime(mustParseDate(t, "2014-05-17"), mustParseTime(t, "23:30:12"), time.UTC)
+	if assert.NotNil(t, r) {
+		assert.True(t, r.HasTimeZone())
+	}
+}
+
+func TestCombineDateTimeDateOnly(t *testing.T) {
+	r := CombineDateTime(mustParseDate(t, "2014-05"), mustParseTime(t, "23:30:12"), nil)
+	if assert.NotNil(t, r) {
+		assert.Equal(t, "2014-05", r.String())
+		assert.Equal(t, MonthDatePrecision, r.Precision())
+	}
+	r = CombineDateTime(mustParseDate(t, "2014-05-17"), nil, nil)
+	if assert.NotNil(t, r) {
+		assert.Equal(t, "2014-05-17", r.String())
+	}
+}
+
+func TestCombineDateTimeNil(t *testing.T) {
+	assert.Nil(t, CombineDateTime(nil, mustParseTime(t, "23:30:12"), nil))
+	assert.Nil(t, CombineDateTime(NewDateNil(), mustParseTime(t, "23:30:12"), nil))
+}
+
+func TestDateFromDateTimeYearOutOfRange(t *testing.T) {
+	assert.Nil(t, DateFromDateTime(NewDateTime(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC))))
+}
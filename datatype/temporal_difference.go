@@ -0,0 +1,222 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"time"
+)
+
+const nanosPerDay = 24 * 60 * 60 * nanosPerSecond
+
+type temporalDifferenceUnit struct {
+	keyword   string
+	precision DateTimePrecisions
+	nanos     int64
+	days      int64
+}
+
+var temporalDifferenceUnits = map[string]temporalDifferenceUnit{
+	"a":   {"years", YearDatePrecision, 0, 0},
+	"mo":  {"months", MonthDatePrecision, 0, 0},
+	"wk":  {"weeks", DayDatePrecision, 0, 7},
+	"d":   {"days", DayDatePrecision, 0, 1},
+	"h":   {"hours", HourTimePrecision, int64(time.Hour), 0},
+	"min": {"minutes", MinuteTimePrecision, int64(time.Minute), 0},
+	"s":   {"seconds", SecondTimePrecision, int64(time.Second), 0},
+	"ms":  {"milliseconds", SecondTimePrecision, int64(time.Millisecond), 0},
+}
+
+type temporalPoint struct {
+	months int64
+	day    int64
+	days   int64
+	nanos  int64
+}
+
+func Duration(start TemporalAccessor, end TemporalAccessor, unit string) (QuantityAccessor, error) {
+	return temporalDifference(start, end, unit, true)
+}
+
+func Difference(start TemporalAccessor, end TemporalAccessor, unit string) (QuantityAccessor, error) {
+	return temporalDifference(start, end, unit, false)
+}
+
+func AgeAt(birthDate DateAccessor, at DateTemporalAccessor) QuantityAccessor {
+	if birthDate == nil || at == nil || CompareTemporal(birthDate, at) == GreaterComparisonResult {
+		return nil
+	}
+
+	months, _ := Duration(birthDate, at, "months")
+	if months == nil {
+		years, _ := Duration(birthDate, at, "years")
+		return years
+	}
+
+	count := months.Value().Int()
+	if count < 1 {
+		if days, _ := Duration(birthDate, at, "days"); days != nil {
+			return days
+		}
+	}
+	if count < 24 {
+		return months
+	}
+	years, _ := Duration(birthDate, at, "years")
+	return years
+}
+
+func temporalDifference(start TemporalAccessor, end TemporalAccessor, unit string, whole bool) (QuantityAccessor, error) {
+	code, found := calendarDurationCodes[unit]
+	if !found {
+		return nil, fmt.Errorf("not a valid calendar duration unit: %s", unit)
+	}
+	u := temporalDifferenceUnits[code]
+
+	if start == nil || end == nil || start.Nil() || end.Nil() {
+		return nil, nil
+	}
+	timeOnly := start.DataType() == TimeDataType
+	if timeOnly != (end.DataType() == TimeDataType) {
+		return nil, nil
+	}
+	if timeOnly && u.precision < HourTimePrecision {
+		return nil, fmt.Errorf("not a time-valued unit for time values: %s", unit)
+	}
+
+	ps, pe := comparisonPrecision(start), comparisonPrecision(end)
+	p := ps
+	if pe < p {
+		p = pe
+	}
+	if u.precision > p {
+		return nil, nil
+	}
+
+	normalize := ps >= HourTimePrecision && pe >= HourTimePrecision
+	s := newTemporalPoint(start, p, normalize)
+	e := newTemporalPoint(end, p, normalize)
+
+	var amount int64
+	switch {
+	case u.precision < DayDatePrecision:
+		amount = monthDifference(s, e, whole)
+		if u.precision == YearDatePrecision {
+			if whole {
+				amount = amount / 12
+			} else {
+				amount = (e.months / 12) - (s.months / 12)
+			}
+		}
+	case u.days == 7 && !whole:
+		amount = weekDifference(s, e)
+	case u.nanos == 0:
+		amount = dayDifference(s, e, whole) / u.days
+	default:
+		amount = nanoDifference(s, e, u.nanos, whole)
+	}
+
+	return NewQuantity(NewDecimalInt64(amount), nil, NewString(u.keyword), UCUMSystemURI, NewCode(code)), nil
+}
+
+func newTemporalPoint(accessor TemporalAccessor, precision DateTimePrecisions, normalize bool) temporalPoint {
+	v := temporalComponentValues(accessor, normalize)
+	for i := precision + 1; i < temporalComponentCount; i++ {
+		if i <= DayDatePrecision {
+			v[i] = 1
+		} else {
+			v[i] = 0
+		}
+	}
+
+	var p temporalPoint
+	if accessor.DataType() != TimeDataType {
+		p.months = v[YearDatePrecision]*12 + v[MonthDatePrecision] - 1
+		p.day = v[DayDatePrecision]
+		p.days = time.Date(int(v[YearDatePrecision]), time.Month(v[MonthDatePrecision]), int(v[DayDatePrecision]),
+			0, 0, 0, 0, time.UTC).Unix() / (nanosPerDay / nanosPerSecond)
+	}
+	p.nanos = v[HourTimePrecision]*int64(time.Hour) + v[MinuteTimePrecision]*int64(time.Minute) + v[SecondTimePrecision]
+	return p
+}
+
+func monthDifference(s temporalPoint, e temporalPoint, whole bool) int64 {
+	amount := e.months - s.months
+	if !whole {
+		return amount
+	}
+
+	if amount > 0 && (e.day < s.day || (e.day == s.day && e.nanos < s.nanos)) {
+		amount--
+	} else if amount < 0 && (e.day > s.day || (e.day == s.day && e.nanos > s.nanos)) {
+		amount++
+	}
+	return amount
+}
+
+func dayDifference(s temporalPoint, e temporalPoint, whole bool) int64 {
+	days, _ := normalizedDayDifference(s, e, whole)
+	return days
+}
+
+func weekDifference(s temporalPoint, e temporalPoint) int64 {
+	return epochWeek(e.days) - epochWeek(s.days)
+}
+
+func epochWeek(days int64) int64 {
+	// weeks start on Monday and 1970-01-01 has been a Thursday
+	days += 3
+	if days < 0 {
+		return (days - 6) / 7
+	}
+	return days / 7
+}
+
+func nanoDifference(s temporalPoint, e temporalPoint, unit int64, whole bool) int64 {
+	days, nanos := normalizedDayDifference(s, e, whole)
+	if whole {
+		return days*(nanosPerDay/unit) + nanos/unit
+	}
+	return days*(nanosPerDay/unit) + e.nanos/unit - s.nanos/unit
+}
+
+func normalizedDayDifference(s temporalPoint, e temporalPoint, whole bool) (int64, int64) {
+	days, nanos := e.days-s.days, e.nanos-s.nanos
+	if !whole {
+		return days, nanos
+	}
+	if days > 0 && nanos < 0 {
+		days--
+		nanos = nanos + nanosPerDay
+	} else if days < 0 && nanos > 0 {
+		days++
+		nanos = nanos - nanosPerDay
+	}
+	return days, nanos
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDurationDates(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		unit     string
+		expected string
+	}{
+		{"2000-02-01", "2000-03-01", "days", "29 days"},
+		{"2000-02-01", "2000-03-01", "months", "1 months"},
+		{"2000-03-15", "2001-03-14", "years", "0 years"},
+		{"2000-03-15", "2001-03-15", "year", "1 years"},
+		{"2000-03-15", "2001-03-14", "months", "11 months"},
+		{"2001-03-14", "2000-03-15", "months", "-11 months"},
+		{"2000-01-31", "2000-02-29", "months", "0 months"},
+		{"2000-02-29", "2001-02-28", "years", "0 years"},
+		{"2000-02-29", "2001-03-01", "years", "1 years"},
+		{"2000-01-01", "2000-01-20", "weeks", "2 weeks"},
+		{"2000-01-01", "2000-01-03", "weeks", "0 weeks"},
+		{"2000", "2010-05-01", "years", "10 years"},
+	}
+	for _, test := range tests {
+		r, err := Duration(mustParseDate(t, test.start), mustParseDate(t, test.end), test.unit)
+		if assert.NoError(t, err) && assert.NotNil(t, r, "result expected for %s %s", test.start, test.end) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s %s %s", test.start, test.end, test.unit)
+		}
+	}
+}
+
+func TestDifferenceDates(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		unit     string
+		expected string
+	}{
+		{"2000-12-31", "2001-01-01", "years", "1 years"},
+		{"2000-12-31", "2001-01-01", "months", "1 months"},
+		{"2000-12-31", "2001-01-01", "days", "1 days"},
+		{"2001-01-01", "2000-12-31", "years", "-1 years"},
+		{"2000-01-01", "2000-01-20", "weeks", "3 weeks"},
+		{"2000-01-01", "2000-01-03", "weeks", "1 weeks"},
+		{"2000-01-03", "2000-01-09", "weeks", "0 weeks"},
+		{"2000-01-09", "2000-01-01", "weeks", "-1 weeks"},
+		{"1969-12-28", "1969-12-29", "weeks", "1 weeks"},
+		{"2000", "2010-05-01", "years", "10 years"},
+	}
+	for _, test := range tests {
+		r, err := Difference(mustParseDate(t, test.start), mustParseDate(t, test.end), test.unit)
+		if assert.NoError(t, err) && assert.NotNil(t, r, "result expected for %s %s", test.start, test.end) {
+			assert.Equal(t, test.expected, r.String(), "unexpected result for %s %s %s", test.start, test.end, test.unit)
+		}
+	}
+}
+
+func TestDurationDateTimes(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		unit     string
+		duration string
+		diff     string
+	}{
+		{"2000-01-01T23:30:00Z", "2000-01-02T00:10:00Z", "days", "0 days", "1 days"},
+		{"2000-01-01T23:30:00Z", "2000-01-02T00:10:00Z", "hours", "0 hours", "1 hours"},
+		{"2000-01-01T23:30:00Z", "2000-01-02T00:10:00Z", "minutes", "40 minutes", "40 minutes"},
+		{"2000-01-02T00:10:00Z", "2000-01-01T23:30:00Z", "minutes", "-40 minutes", "-40 minutes"},
+		{"2000-01-01T10:00:00+02:00", "2000-01-01T10:00:00Z", "hours", "2 hours", "2 hours"},
+		{"2000-01-01T10:00:00.250Z", "2000-01-01T10:00:01.100Z", "seconds", "0 seconds", "1 seconds"},
+		{"2000-01-01T10:00:00.250Z", "2000-01-01T10:00:01.100Z", "milliseconds", "850 milliseconds", "850 milliseconds"},
+		{"2000-01-01T10:00:00Z", "2010-01-01T09:00:00Z", "years", "9 years", "10 years"},
+		{"2000-01-01T10:00:00Z", "2400-01-01T10:00:00Z", "milliseconds",
+			"12622780800000 milliseconds", "12622780800000 milliseconds"},
+	}
+	for _, test := range tests {
+		start, end := mustParseDateTime(t, test.start), mustParseDateTime(t, test.end)
+		r, err := Duration(start, end, test.unit)
+		if assert.NoError(t, err) && assert.NotNil(t, r, "result expected for %s %s", test.start, test.end) {
+			assert.Equal(t, test.duration, r.String(), "unexpected duration for %s %s %s", test.start, test.end, test.unit)
+		}
+		r, err = Difference(start, end, test.unit)
+		if assert.NoError(t, err) && assert.NotNil(t, r, "result expected for %s %s", test.start, test.end) {
+			assert.Equal(t, test.diff, r.String(), "unexpected difference for %s %s %s", test.start, test.end, test.unit)
+		}
+	}
+}
+
+func TestDurationTimes(t *testing.T) {
+	r, err := Duration(mustParseTime(t, "10:30:00"), mustParseTime(t, "12:29:59"), "hours")
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "1 hours", r.String())
+	}
+	r, err = Difference(mustParseTime(t, "10:30:00"), mustParseTime(t, "12:29:59"), "hours")
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "2 hours", r.String())
+	}
+}
+
+func TestDurationTimeDateUnit(t *testing.T) {
+	r, err := Duration(mustParseTime(t, "10:30:00"), mustParseTime(t, "12:29:59"), "days")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+}
+
+func TestDurationInvalidUnit(t *testing.T) {
+	r, err := Duration(mustParseDate(t, "2000-01-01"), mustParseDate(t, "2001-01-01"), "fortnights")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+}
+
+func TestDurationQuantityUnit(t *testing.T) {
+	r, err := Duration(mustParseDate(t, "2000-01-01"), mustParseDate(t, "2001-01-01"), "year")
+	if assert.NoError(t, err) && assert.NotNil(t, r) {
+		assert.Equal(t, "years", StringValue(r.Unit()))
+		assert.Equal(t, "a", StringValue(r.Code()))
+		assert.Equal(t, UCUMSystemURI.String(), StringValue(r.System()))
+		assert.Equal(t, int32(1), r.Value().Int())
+	}
+}
+
+func TestDurationEmpty(t *testing.T) {
+	tests := []struct {
+		start TemporalAccessor
+		end   TemporalAccessor
+		unit  string
+	}{
+		{nil, mustParseDate(t, "2000-01-01"), "days"},
+		{mustParseDate(t, "2000-01-01"), NewDateNil(), "days"},
+		{mustParseDate(t, "2000-01-01"), mustParseTime(t, "10:00:00"), "hours"},
+		{mustParseDate(t, "2000-01"), mustParseDate(t, "2000-01-01"), "days"},
+		{mustParseDate(t, "2000-01-01"), mustParseDate(t, "2000-01-02"), "hours"},
+	}
+	for _, test := range tests {
+		r, err := Duration(test.start, test.end, test.unit)
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+		r, err = Difference(test.start, test.end, test.unit)
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+	}
+}
+
+func TestAgeAt(t *testing.T) {
+	tests := []struct {
+		birthDate string
+		at        string
+		expected  string
+	}{
+		{"2020-03-10", "2020-03-10", "0 days"},
+		{"2020-03-10", "2020-04-09", "30 days"},
+		{"2020-03-10", "2020-04-10", "1 months"},
+		{"2020-03-10", "2022-03-09", "23 months"},
+		{"2020-03-10", "2022-03-10", "2 years"},
+		{"1970-07-20", "2020-07-19", "49 years"},
+		{"1970", "2020-07-19", "50 years"},
+	}
+	for _, test := range tests {
+		r := AgeAt(mustParseDate(t, test.birthDate), mustParseDate(t, test.at))
+		if assert.NotNil(t, r, "result expected for %s %s", test.birthDate, test.at) {
+			assert.Equal(t, test.expected, r.String(), "unexpected age for %s %s", test.birthDate, test.at)
+		}
+	}
+}
+
+func TestAgeAtDateTime(t *testing.T) {
+	r := AgeAt(mustParseDate(t, "1970-07-20"), mustParseDateTime(t, "2020-07-20T08:00:00Z"))
+	if assert.NotNil(t, r) {
+		assert.Equal(t, "50 years", r.String())
+	}
+}
+
+func TestAgeAtBeforeBirth(t *testing.T) {
+	assert.Nil(t, AgeAt(mustParseDate(t, "2020-03-10"), mustParseDate(t, "2020-03-09")))
+}
+
+func TestAgeAtNil(t *testing.T) {
+	assert.Nil(t, AgeAt(nil, mustParseDate(t, "2020-03-09")))
+	assert.Nil(t, AgeAt(NewDateNil(), mustParseDate(t, "2020-03-09")))
+}
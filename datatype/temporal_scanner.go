@@ -0,0 +1,253 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const maxZoneOffsetMinutes = 14 * 60
+
+var fixedZones [2*maxZoneOffsetMinutes + 1]atomic.Value
+
+type temporalFields struct {
+	year       int
+	month      int
+	day        int
+	hour       int
+	minute     int
+	second     int
+	nanosecond int
+	precision  DateTimePrecisions
+	offset     int
+	zone       bool
+}
+
+type temporalScanner struct {
+	value  string
+	pos    int
+	reason ParseErrorReasons
+}
+
+func scanDate(value string, f *temporalFields) (int, ParseErrorReasons, bool) {
+	s := temporalScanner{value: value}
+	if !s.scanDate(f) || !s.end() {
+		return s.pos, s.reason, false
+	}
+	return 0, 0, true
+}
+
+func scanDateTime(value string, f *temporalFields) (int, ParseErrorReasons, bool) {
+	s := temporalScanner{value: value}
+	if !s.scanDate(f) {
+		return s.pos, s.reason, false
+	}
+	if f.precision == DayDatePrecision && s.next('T') {
+		if !s.scanTime(f) || !s.scanZone(f) {
+			return s.pos, s.reason, false
+		}
+	}
+	if !s.end() {
+		return s.pos, s.reason, false
+	}
+	return 0, 0, true
+}
+
+func scanTime(value string, f *temporalFields) (int, ParseErrorReasons, bool) {
+	s := temporalScanner{value: value}
+	if !s.scanTime(f) || !s.end() {
+		return s.pos, s.reason, false
+	}
+	return 0, 0, true
+}
+
+func (s *temporalScanner) scanDate(f *temporalFields) bool {
+	var ok bool
+	if f.year, ok = s.number(4, 1, 9999); !ok {
+		return false
+	}
+	f.month, f.day, f.precision = 1, 1, YearDatePrecision
+	if !s.next('-') {
+		return true
+	}
+
+	if f.month, ok = s.number(2, 1, 12); !ok {
+		return false
+	}
+	f.precision = MonthDatePrecision
+	if !s.next('-') {
+		return true
+	}
+
+	if f.day, ok = s.number(2, 1, daysInMonth(f.year, time.Month(f.month))); !ok {
+		return false
+	}
+	f.precision = DayDatePrecision
+	return true
+}
+
+func (s *temporalScanner) scanTime(f *temporalFields) bool {
+	var ok bool
+	if f.hour, ok = s.number(2, 0, 23); !ok {
+		return false
+	}
+	if !s.expect(':') {
+		return false
+	}
+	if f.minute, ok = s.number(2, 0, 59); !ok {
+		return false
+	}
+	if !s.expect(':') {
+		return false
+	}
+	if f.second, ok = s.number(2, 0, 60); !ok {
+		return false
+	}
+	f.precision = SecondTimePrecision
+	if !s.next('.') {
+		return true
+	}
+
+	start := s.pos
+	nanosecond, digits := 0, 0
+	for s.pos < len(s.value) && isDigit(s.value[s.pos]) {
+		if digits < 9 {
+			nanosecond = nanosecond*10 + int(s.value[s.pos]-'0')
+			digits++
+		}
+		s.pos++
+	}
+	if s.pos == start {
+		return s.fail(InvalidSyntaxParseErrorReason)
+	}
+	for ; digits < 9; digits++ {
+		nanosecond = nanosecond * 10
+	}
+	f.nanosecond, f.precision = nanosecond, NanoTimePrecision
+	return true
+}
+
+func (s *temporalScanner) scanZone(f *temporalFields) bool {
+	if s.next('Z') {
+		f.zone, f.offset = true, 0
+		return true
+	}
+
+	sign := 1
+	if s.next('-') {
+		sign = -1
+	} else if !s.expect('+') {
+		return false
+	}
+
+	hourPos := s.pos
+	hour, ok := s.number(2, 0, 99)
+	if !ok || !s.expect(':') {
+		return false
+	}
+	minute, ok := s.number(2, 0, 99)
+	if !ok {
+		return false
+	}
+	if hour > 14 || (hour == 14 && minute > 0) {
+		s.pos = hourPos
+		return s.fail(OutOfRangeParseErrorReason)
+	}
+	if minute > 59 {
+		s.pos = hourPos + 3
+		return s.fail(OutOfRangeParseErrorReason)
+	}
+
+	f.zone, f.offset = true, sign*(hour*60+minute)*60
+	return true
+}
+
+func (s *temporalScanner) number(digits int, min int, max int) (int, bool) {
+	start := s.pos
+	v := 0
+	for s.pos < len(s.value) && s.pos-start < digits && isDigit(s.value[s.pos]) {
+		v = v*10 + int(s.value[s.pos]-'0')
+		s.pos++
+	}
+	if s.pos-start < digits {
+		return 0, s.fail(InvalidSyntaxParseErrorReason)
+	}
+	if v < min || v > max {
+		s.pos = start
+		return 0, s.fail(OutOfRangeParseErrorReason)
+	}
+	return v, true
+}
+
+func (s *temporalScanner) next(c byte) bool {
+	if s.pos < len(s.value) && s.value[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+func (s *temporalScanner) expect(c byte) bool {
+	if !s.next(c) {
+		return s.fail(InvalidSyntaxParseErrorReason)
+	}
+	return true
+}
+
+func (s *temporalScanner) end() bool {
+	if s.pos < len(s.value) {
+		return s.fail(InvalidSyntaxParseErrorReason)
+	}
+	return true
+}
+
+func (s *temporalScanner) fail(reason ParseErrorReasons) bool {
+	s.reason = reason
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func fixedZone(offset int) *time.Location {
+	if offset == 0 {
+		return time.UTC
+	}
+
+	cache := &fixedZones[offset/60+maxZoneOffsetMinutes]
+	if location, ok := cache.Load().(*time.Location); ok {
+		return location
+	}
+	location := time.FixedZone(fmt.Sprint(offset), offset)
+	cache.Store(location)
+	return location
+}
@@ -0,0 +1,312 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var timeZoneOffsetRegexp = regexp.MustCompile("^([+-])(\\d{1,2})(?::(\\d{1,2}))$")
+var dateRegexp = regexp.MustCompile("^(\\d(?:\\d(?:\\d[1-9]|[1-9]0)|[1-9]00)|[1-9]000)(?:-(0[1-9]|1[0-2])(?:-(0[1-9]|[1-2]\\d|3[0-1]))?)?$")
+var timeRegexp = regexp.MustCompile("^([01]\\d|2[0-3]):([0-5]\\d):([0-5]\\d|60)(?:\\.(\\d+))?$")
+var dateTimeRegexp = regexp.MustCompile("^(\\d(?:\\d(?:\\d[1-9]|[1-9]0)|[1-9]00)|[1-9]000)(?:-(0[1-9]|1[0-2])(?:-(0[1-9]|[1-2]\\d|3[0-1])(?:T([01]\\d|2[0-3]):([0-5]\\d):([0-5]\\d|60)(?:\\.(\\d+))?(Z|[+-](?:(?:0\\d|1[0-3]):[0-5]\\d|14:00)))?)?)?$")
+
+func regexpParseDate(value string) (DateAccessor, error) {
+	parts := dateRegexp.FindStringSubmatch(value)
+	if parts == nil {
+		return nil, fmt.Errorf("not a valid date: %s", value)
+	}
+
+	year, _ := strconv.Atoi(parts[1])
+	month, day, precision := 1, 1, YearDatePrecision
+	if parts[2] != "" {
+		month, _ = strconv.Atoi(parts[2])
+		precision = MonthDatePrecision
+	}
+	if parts[3] != "" {
+		day, _ = strconv.Atoi(parts[3])
+		precision = DayDatePrecision
+	}
+	return newDate(false, year, month, day, precision), nil
+}
+
+func regexpParseTime(value string) (TimeAccessor, error) {
+	parts := timeRegexp.FindStringSubmatch(value)
+	if parts == nil {
+		return nil, fmt.Errorf("not a valid time: %s", value)
+	}
+
+	hour, _ := strconv.Atoi(parts[1])
+	minute, _ := strconv.Atoi(parts[2])
+	second, _ := strconv.Atoi(parts[3])
+	nanosecond, precision := 0, SecondTimePrecision
+	if parts[4] != "" {
+		nanosecond = parseNanosecond(parts[4])
+		precision = NanoTimePrecision
+	}
+	return newTime(false, hour, minute, second, nanosecond, precision), nil
+}
+
+func regexpParseDateTime(value string) (DateTimeAccessor, error) {
+	parts := dateTimeRegexp.FindStringSubmatch(value)
+	if parts == nil {
+		return nil, fmt.Errorf("not a valid dateTime: %s", value)
+	}
+
+	year, _ := strconv.Atoi(parts[1])
+	month, day, precision := 1, 1, YearDatePrecision
+	if parts[2] != "" {
+		month, _ = strconv.Atoi(parts[2])
+		precision = MonthDatePrecision
+	}
+	if parts[3] != "" {
+		day, _ = strconv.Atoi(parts[3])
+		precision = DayDatePrecision
+	}
+	hour, minute, second, nanosecond := 0, 0, 0, 0
+	if parts[4] != "" {
+		hour, _ = strconv.Atoi(parts[4])
+		minute, _ = strconv.Atoi(parts[5])
+		second, _ = strconv.Atoi(parts[6])
+		precision = SecondTimePrecision
+	}
+	if parts[7] != "" {
+		nanosecond = parseNanosecond(parts[7])
+		precision = NanoTimePrecision
+	}
+
+	location := mustEvalLocation(parts[8], time.Local)
+	return newDateTime(false, time.Date(year, time.Month(month), day,
+		hour, minute, second, nanosecond, location), precision, parts[8] != ""), nil
+}
+
+func parseNanosecond(value string) int {
+	if value == "" {
+		return 0
+	}
+	nanoValue := value
+	if len(nanoValue) > 9 {
+		nanoValue = nanoValue[0:9]
+	}
+	nano, _ := strconv.Atoi(nanoValue)
+	nano = nano * int(math.Pow10(9-len(nanoValue)))
+	return nano
+}
+
+func mustEvalLocation(value string, defaultLocation *time.Location) *time.Location {
+	if value == "" {
+		return defaultLocation
+	}
+	if value == "Z" {
+		return time.UTC
+	}
+
+	parts := timeZoneOffsetRegexp.FindStringSubmatch(value)
+	if parts == nil {
+		panic(fmt.Sprintf("not a valid time zone offset: %s", value))
+	}
+
+	hours, _ := strconv.Atoi(parts[2])
+	offset := hours * 60 * 60
+	if parts[3] != "" {
+		minutes, _ := strconv.Atoi(parts[3])
+		offset = offset + (minutes * 60)
+	}
+	if parts[1] == "-" {
+		offset = -offset
+	}
+	if offset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone(fmt.Sprint(offset), offset)
+}
+
+var scannerDateTimeValues = []string{
+	"2014",
+	"2014-05",
+	"2014-05-17",
+	"2014-05-17T23:30:12Z",
+	"2014-05-17T23:30:12.123+05:30",
+	"2014-05-17T23:30:12.1234567891-14:00",
+	"2020-02-29T00:00:00+14:00",
+	"0001-01-01T00:00:60Z",
+}
+
+var scannerTimeValues = []string{
+	"00:00:00",
+	"23:59:60",
+	"12:30:15.5",
+	"12:30:15.1234567891",
+}
+
+func TestScanDateTimeMatchesRegexp(t *testing.T) {
+	for _, value := range scannerDateTimeValues {
+		expected, err := regexpParseDateTime(value)
+		if !assert.NoError(t, err, "regexp must accept %s", value) {
+			continue
+		}
+		actual, err := ParseDateTime(value)
+		if assert.NoError(t, err, "scanner must accept %s", value) {
+			assert.Equal(t, expected.Precision(), actual.Precision(), value)
+			assert.True(t, expected.Time().Equal(actual.Time()), value)
+			assert.Equal(t, expected.String(), actual.String(), value)
+		}
+	}
+}
+
+func TestScanDateMatchesRegexp(t *testing.T) {
+	for _, value := range []string{"0001", "2014", "2014-05", "2014-05-17", "2020-02-29", "9999-12-31"} {
+		expected, err := regexpParseDate(value)
+		if !assert.NoError(t, err, "regexp must accept %s", value) {
+			continue
+		}
+		actual, err := ParseDate(value)
+		if assert.NoError(t, err, "scanner must accept %s", value) {
+			assert.Equal(t, expected, actual, value)
+		}
+	}
+}
+
+func TestScanTimeMatchesRegexp(t *testing.T) {
+	for _, value := range scannerTimeValues {
+		expected, err := regexpParseTime(value)
+		if !assert.NoError(t, err, "regexp must accept %s", value) {
+			continue
+		}
+		actual, err := ParseTime(value)
+		if assert.NoError(t, err, "scanner must accept %s", value) {
+			assert.Equal(t, expected, actual, value)
+		}
+	}
+}
+
+func TestScanDateCalendarDay(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+	}{
+		{"2021-02-29", 8},
+		{"2021-02-31", 8},
+		{"2021-04-31", 8},
+		{"1900-02-29", 8},
+	}
+	for _, test := range tests {
+		_, err := ParseDate(test.value)
+		assertParseError(t, err, DateDataType, test.position, OutOfRangeParseErrorReason)
+		_, err = ParseDateTime(test.value)
+		assertParseError(t, err, DateTimeDataType, test.position, OutOfRangeParseErrorReason)
+	}
+}
+
+func TestScanDateLeapDay(t *testing.T) {
+	for _, value := range []string{"2000-02-29", "2020-02-29", "2021-01-31"} {
+		_, err := ParseDate(value)
+		assert.NoError(t, err, "no error expected for %s", value)
+	}
+}
+
+func TestScanDateTimeTimeAfterMonth(t *testing.T) {
+	_, err := ParseDateTime("2014-05T10:00:00Z")
+	assertParseError(t, err, DateTimeDataType, 7, InvalidSyntaxParseErrorReason)
+}
+
+func TestScanDateTimeNoZoneAfterTime(t *testing.T) {
+	_, err := ParseDateTime("2014-05-17T")
+	assertParseError(t, err, DateTimeDataType, 11, InvalidSyntaxParseErrorReason)
+}
+
+func TestScanTimeEmptyFraction(t *testing.T) {
+	_, err := ParseTime("10:00:00.x")
+	assertParseError(t, err, TimeDataType, 9, InvalidSyntaxParseErrorReason)
+}
+
+func TestFixedZoneCached(t *testing.T) {
+	l1 := fixedZone(-5 * 60 * 60)
+	l2 := fixedZone(-5 * 60 * 60)
+	assert.Same(t, l1, l2)
+	_, offset := time.Date(2020, 1, 1, 0, 0, 0, 0, l1).Zone()
+	assert.Equal(t, -5*60*60, offset)
+	assert.Same(t, time.UTC, fixedZone(0))
+}
+
+func TestScanDateTimeAllocations(t *testing.T) {
+	var f temporalFields
+	allocs := testing.AllocsPerRun(100, func() {
+		scanDateTime("2014-05-17T23:30:12.123+05:30", &f)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkParseDateTime(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseDateTime(scannerDateTimeValues[i%len(scannerDateTimeValues)])
+	}
+}
+
+func BenchmarkRegexpParseDateTime(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = regexpParseDateTime(scannerDateTimeValues[i%len(scannerDateTimeValues)])
+	}
+}
+
+func BenchmarkParseDate(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseDate("2014-05-17")
+	}
+}
+
+func BenchmarkRegexpParseDate(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = regexpParseDate("2014-05-17")
+	}
+}
+
+func BenchmarkParseTime(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseTime(scannerTimeValues[i%len(scannerTimeValues)])
+	}
+}
+
+func BenchmarkRegexpParseTime(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = regexpParseTime(scannerTimeValues[i%len(scannerTimeValues)])
+	}
+}
@@ -29,17 +29,12 @@
 package datatype
 
 import (
-	"fmt"
-	"math"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
 
-var timeTypeSpec = newElementTypeSpec("time")
-
-var timeRegexp = regexp.MustCompile("^([01]\\d|2[0-3]):([0-5]\\d):([0-5]\\d|60)(?:\\.(\\d+))?$")
+var timeTypeSpec = newElementTypeSpec("time").SetProperties(
+	newPrimitiveValueProperty(systemTimeTypeSpec))
 
 type timeType struct {
 	TemporalType
@@ -55,6 +50,10 @@ type TimeAccessor interface {
 	Minute() int
 	Second() int
 	Nanosecond() int
+	Add(quantity QuantityAccessor) (TimeAccessor, error)
+	Subtract(quantity QuantityAccessor) (TimeAccessor, error)
+	LowBoundary(precision DateTimePrecisions) TimeAccessor
+	HighBoundary(precision DateTimePrecisions) TimeAccessor
 }
 
 func NewTimeNil() TimeAccessor {
@@ -91,36 +90,11 @@ func NewTimeHMSNWithPrecision(hour int, minute int, second int, nanosecond int,
 }
 
 func ParseTime(value string) (TimeAccessor, error) {
-	parts := timeRegexp.FindStringSubmatch(value)
-	if parts == nil {
-		return nil, fmt.Errorf("not a valid time string: %s", value)
-	}
-	return newTimeFromParts(parts), nil
-}
-
-func newTimeFromParts(parts []string) TimeAccessor {
-	hour, _ := strconv.Atoi(parts[1])
-	precision := HourTimePrecision
-
-	minute := 0
-	if parts[2] != "" {
-		minute, _ = strconv.Atoi(parts[2])
-		precision = MinuteTimePrecision
-	}
-
-	second := 0
-	if parts[3] != "" {
-		second, _ = strconv.Atoi(parts[3])
-		precision = SecondTimePrecision
-	}
-
-	nanosecond := 0
-	if parts[4] != "" {
-		nanosecond = parseNanosecond(parts[4])
-		precision = NanoTimePrecision
+	var f temporalFields
+	if pos, reason, ok := scanTime(value, &f); !ok {
+		return nil, NewParseError(TimeDataType, value, pos, reason)
 	}
-
-	return newTime(false, hour, minute, second, nanosecond, precision)
+	return newTime(false, f.hour, f.minute, f.second, f.nanosecond, f.precision), nil
 }
 
 func newTime(nilValue bool, hour int, minute int, second int, nanosecond int, precision DateTimePrecisions) TimeAccessor {
@@ -138,19 +112,6 @@ func newTime(nilValue bool, hour int, minute int, second int, nanosecond int, pr
 	}
 }
 
-func parseNanosecond(value string) int {
-	if value == "" {
-		return 0
-	}
-	nanoValue := value
-	if len(nanoValue) > 9 {
-		nanoValue = nanoValue[0:9]
-	}
-	nano, _ := strconv.Atoi(nanoValue)
-	nano = nano * int(math.Pow10(9-len(nanoValue)))
-	return nano
-}
-
 func (t *timeType) DataType() DataTypes {
 	return TimeDataType
 }
@@ -175,6 +136,14 @@ func (t *timeType) Nanosecond() int {
 	return t.nanosecond
 }
 
+func (t *timeType) Add(quantity QuantityAccessor) (TimeAccessor, error) {
+	return addTime(t, quantity, false)
+}
+
+func (t *timeType) Subtract(quantity QuantityAccessor) (TimeAccessor, error) {
+	return addTime(t, quantity, true)
+}
+
 func (t *timeType) TypeSpec() TypeSpecAccessor {
 	return timeTypeSpec
 }
@@ -278,3 +278,21 @@ func TestNewTimeHMSNWithPrecisionInvalid(t *testing.T) {
 	assert.Equal(t, 231, v.Nanosecond())
 	assert.Equal(t, NanoTimePrecision, v.Precision())
 }
+
+func TestParseTimeInvalidPosition(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		reason   ParseErrorReasons
+	}{
+		{"24:00:00", 0, OutOfRangeParseErrorReason},
+		{"12:60:00", 3, OutOfRangeParseErrorReason},
+		{"12:30", 5, InvalidSyntaxParseErrorReason},
+		{"12:30:00.", 9, InvalidSyntaxParseErrorReason},
+		{"12:30:00Z", 8, InvalidSyntaxParseErrorReason},
+	}
+	for _, test := range tests {
+		_, err := ParseTime(test.value)
+		assertParseError(t, err, TimeDataType, test.position, test.reason)
+	}
+}
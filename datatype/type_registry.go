@@ -0,0 +1,211 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type TypeRegistry struct {
+	lock   sync.RWMutex
+	fqName map[string]TypeSpecAccessor
+	name   map[string][]TypeSpecAccessor
+}
+
+var defaultTypeRegistry = newDefaultTypeRegistry()
+
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{
+		fqName: make(map[string]TypeSpecAccessor),
+		name:   make(map[string][]TypeSpecAccessor),
+	}
+}
+
+func newDefaultTypeRegistry() *TypeRegistry {
+	r := NewTypeRegistry()
+	for _, spec := range []TypeSpecAccessor{
+		elementTypeSpec,
+		extensionTypeSpec,
+		booleanTypeSpec,
+		integerTypeSpec,
+		unsignedIntTypeSpec,
+		positiveIntTypeSpec,
+		stringTypeSpec,
+		codeTypeSpec,
+		idTypeSpec,
+		markdownTypeSpec,
+		decimalTypeSpec,
+		uriTypeSpec,
+		dateTypeSpec,
+		dateTimeTypeSpec,
+		timeTypeSpec,
+		quantityTypeSpec,
+		systemAnyTypeSpec,
+		systemBooleanTypeSpec,
+		systemStringTypeSpec,
+		systemIntegerTypeSpec,
+		systemDecimalTypeSpec,
+		systemDateTypeSpec,
+		systemDateTimeTypeSpec,
+		systemTimeTypeSpec,
+		systemQuantityTypeSpec,
+	} {
+		r.MustRegister(spec)
+	}
+	return r
+}
+
+func DefaultTypeRegistry() *TypeRegistry {
+	return defaultTypeRegistry
+}
+
+func RegisterTypeSpec(spec TypeSpecAccessor) error {
+	return defaultTypeRegistry.Register(spec)
+}
+
+func MustRegisterTypeSpec(spec TypeSpecAccessor) TypeSpecAccessor {
+	return defaultTypeRegistry.MustRegister(spec)
+}
+
+func LoadOrRegisterTypeSpec(spec TypeSpecAccessor) TypeSpecAccessor {
+	return defaultTypeRegistry.LoadOrRegister(spec)
+}
+
+func LookupTypeSpec(fqName string) TypeSpecAccessor {
+	return defaultTypeRegistry.Lookup(fqName)
+}
+
+func TypeSpecs() []TypeSpecAccessor {
+	return defaultTypeRegistry.TypeSpecs()
+}
+
+func (r *TypeRegistry) Register(spec TypeSpecAccessor) error {
+	if err := checkTypeSpecName(spec); err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if registered := r.fqName[spec.String()]; registered != nil {
+		if registered == spec {
+			return nil
+		}
+		return fmt.Errorf("type has already been registered: %s", spec)
+	}
+	r.add(spec)
+	return nil
+}
+
+func (r *TypeRegistry) LoadOrRegister(spec TypeSpecAccessor) TypeSpecAccessor {
+	if err := checkTypeSpecName(spec); err != nil {
+		panic(err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if registered := r.fqName[spec.String()]; registered != nil {
+		return registered
+	}
+	r.add(spec)
+	return spec
+}
+
+func checkTypeSpecName(spec TypeSpecAccessor) error {
+	if spec == nil || spec.FQName() == nil || spec.FQName().Name() == "" {
+		return fmt.Errorf("type specification without name cannot be registered")
+	}
+	return nil
+}
+
+func (r *TypeRegistry) add(spec TypeSpecAccessor) {
+	fqName := spec.FQName()
+	r.fqName[fqName.String()] = spec
+	r.name[fqName.Name()] = append(r.name[fqName.Name()], spec)
+}
+
+func (r *TypeRegistry) MustRegister(spec TypeSpecAccessor) TypeSpecAccessor {
+	if err := r.Register(spec); err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+func (r *TypeRegistry) Lookup(fqName string) TypeSpecAccessor {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if spec, found := r.fqName[fqName]; found {
+		return spec
+	}
+	if strings.IndexByte(fqName, '.') >= 0 {
+		return nil
+	}
+
+	var result TypeSpecAccessor
+	for _, spec := range r.name[fqName] {
+		if spec.FQName().Namespace() == FHIRNamespaceName {
+			return spec
+		}
+		if result == nil {
+			result = spec
+		}
+	}
+	return result
+}
+
+func (r *TypeRegistry) TypeSpecs() []TypeSpecAccessor {
+	r.lock.RLock()
+	specs := make([]TypeSpecAccessor, 0, len(r.fqName))
+	for _, spec := range r.fqName {
+		specs = append(specs, spec)
+	}
+	r.lock.RUnlock()
+
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].String() < specs[j].String()
+	})
+	return specs
+}
+
+func IsSubtypeOf(spec TypeSpecAccessor, base TypeSpecAccessor) bool {
+	if base == nil {
+		return false
+	}
+	for t := spec; t != nil; t = t.Base() {
+		if t.Equal(base) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func replaceDefaultTypeRegistry(t *testing.T) {
+	previous := defaultTypeRegistry
+	defaultTypeRegistry = newDefaultTypeRegistry()
+	t.Cleanup(func() { defaultTypeRegistry = previous })
+}
+
+func TestLookupTypeSpecFQName(t *testing.T) {
+	assert.Same(t, quantityTypeSpec, LookupTypeSpec("FHIR.Quantity"))
+	assert.Same(t, elementTypeSpec, LookupTypeSpec("FHIR.Element"))
+}
+
+func TestLookupTypeSpecName(t *testing.T) {
+	assert.Same(t, codeTypeSpec, LookupTypeSpec("code"))
+	assert.Same(t, dateTimeTypeSpec, LookupTypeSpec("dateTime"))
+}
+
+func TestLookupTypeSpecUnknown(t *testing.T) {
+	assert.Nil(t, LookupTypeSpec("FHIR.Unknown"))
+	assert.Nil(t, LookupTypeSpec("Unknown"))
+	assert.Nil(t, LookupTypeSpec("Other.code"))
+	assert.Nil(t, LookupTypeSpec(""))
+}
+
+func TestTypeSpecs(t *testing.T) {
+	specs := TypeSpecs()
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.String()
+	}
+	assert.Contains(t, names, "FHIR.Quantity")
+	assert.Contains(t, names, "FHIR.positiveInt")
+	for i := 1; i < len(names); i++ {
+		assert.True(t, names[i-1] < names[i], "sorted type names expected")
+	}
+}
+
+func TestTypeRegistryRegister(t *testing.T) {
+	r := NewTypeRegistry()
+	spec := NewTypeSpecWithBase(NewFQTypeName("VitalSigns", "Profile"), quantityTypeSpec)
+	assert.NoError(t, r.Register(spec))
+	assert.NoError(t, r.Register(spec))
+	assert.Same(t, spec, r.Lookup("Profile.VitalSigns"))
+	assert.Same(t, spec, r.Lookup("VitalSigns"))
+	if specs := r.TypeSpecs(); assert.Len(t, specs, 1) {
+		assert.Same(t, spec, specs[0])
+	}
+	assert.Nil(t, r.Lookup("FHIR.Quantity"))
+}
+
+func TestTypeRegistryRegisterDuplicate(t *testing.T) {
+	r := NewTypeRegistry()
+	r.MustRegister(NewTypeSpec(NewFQTypeName("Test", "Model")))
+	err := r.Register(NewTypeSpec(NewFQTypeName("Test", "Model")))
+	if assert.Error(t, err) {
+		assert.Equal(t, "type has already been registered: Model.Test", err.Error())
+	}
+}
+
+func TestTypeRegistryRegisterNil(t *testing.T) {
+	r := NewTypeRegistry()
+	assert.Error(t, r.Register(nil))
+	assert.Error(t, r.Register(NewTypeSpec(nil)))
+	assert.Error(t, r.Register(NewTypeSpec(NewFQTypeName("", "Model"))))
+}
+
+func TestTypeRegistryMustRegisterDuplicate(t *testing.T) {
+	r := NewTypeRegistry()
+	r.MustRegister(NewTypeSpec(NewFQTypeName("Test", "Model")))
+	assert.Panics(t, func() { r.MustRegister(NewTypeSpec(NewFQTypeName("Test", "Model"))) })
+}
+
+func TestTypeRegistryLookupNamePrefersFHIR(t *testing.T) {
+	r := NewTypeRegistry()
+	custom := r.MustRegister(NewTypeSpec(NewFQTypeName("Test", "Model")))
+	other := r.MustRegister(NewTypeSpec(NewFQTypeName("Test", "Other")))
+	assert.Same(t, custom, r.Lookup("Test"))
+	fhir := r.MustRegister(NewTypeSpec(NewFQTypeName("Test", FHIRNamespaceName)))
+	assert.Same(t, fhir, r.Lookup("Test"))
+	assert.Same(t, other, r.Lookup("Other.Test"))
+}
+
+func TestRegisterTypeSpec(t *testing.T) {
+	replaceDefaultTypeRegistry(t)
+	spec := NewTypeSpecWithBase(NewFQTypeName("TestLogicalModel", "Custom"), elementTypeSpec)
+	assert.NoError(t, RegisterTypeSpec(spec))
+	assert.Same(t, spec, LookupTypeSpec("Custom.TestLogicalModel"))
+	assert.Same(t, spec, DefaultTypeRegistry().Lookup("TestLogicalModel"))
+	assert.Error(t, RegisterTypeSpec(NewTypeSpec(NewFQTypeName("string", FHIRNamespaceName))))
+}
+
+func TestIsSubtypeOf(t *testing.T) {
+	assert.True(t, IsSubtypeOf(codeTypeSpec, stringTypeSpec))
+	assert.True(t, IsSubtypeOf(codeTypeSpec, elementTypeSpec))
+	assert.True(t, IsSubtypeOf(positiveIntTypeSpec, integerTypeSpec))
+	assert.True(t, IsSubtypeOf(stringTypeSpec, stringTypeSpec))
+	assert.True(t, IsSubtypeOf(NewTypeSpec(NewFQTypeName("string", FHIRNamespaceName)), stringTypeSpec))
+}
+
+func TestIsSubtypeOfNot(t *testing.T) {
+	assert.False(t, IsSubtypeOf(stringTypeSpec, codeTypeSpec))
+	assert.False(t, IsSubtypeOf(codeTypeSpec, integerTypeSpec))
+	assert.False(t, IsSubtypeOf(quantityTypeSpec, stringTypeSpec))
+}
+
+func TestIsSubtypeOfNil(t *testing.T) {
+	assert.False(t, IsSubtypeOf(nil, stringTypeSpec))
+	assert.False(t, IsSubtypeOf(stringTypeSpec, nil))
+	assert.False(t, IsSubtypeOf(nil, nil))
+}
+
+func TestTypeRegistryLoadOrRegister(t *testing.T) {
+	r := NewTypeRegistry()
+	spec := NewTypeSpec(NewFQTypeName("Test", "Model"))
+	assert.Same(t, spec, r.LoadOrRegister(spec))
+	assert.Same(t, spec, r.LoadOrRegister(NewTypeSpec(NewFQTypeName("Test", "Model"))))
+	assert.Same(t, spec, r.Lookup("Model.Test"))
+}
+
+func TestTypeRegistryLoadOrRegisterNil(t *testing.T) {
+	r := NewTypeRegistry()
+	assert.Panics(t, func() { r.LoadOrRegister(nil) })
+}
+
+func TestLoadOrRegisterTypeSpec(t *testing.T) {
+	replaceDefaultTypeRegistry(t)
+	assert.Same(t, stringTypeSpec, LoadOrRegisterTypeSpec(NewTypeSpec(NewFQTypeName("string", FHIRNamespaceName))))
+	spec := NewTypeSpec(NewFQTypeName("TestLoadOrRegister", "Custom"))
+	assert.Same(t, spec, LoadOrRegisterTypeSpec(spec))
+	assert.Same(t, spec, LookupTypeSpec("Custom.TestLoadOrRegister"))
+}
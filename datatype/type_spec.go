@@ -29,6 +29,7 @@
 package datatype
 
 const FHIRNamespaceName = "FHIR"
+const SystemNamespaceName = "System"
 
 type FQTypeName struct {
 	namespace string
@@ -44,8 +45,9 @@ type FQTypeNameAccessor interface {
 }
 
 type TypeSpec struct {
-	base   TypeSpecAccessor
-	fqName FQTypeNameAccessor
+	base       TypeSpecAccessor
+	fqName     FQTypeNameAccessor
+	properties []TypeSpecPropertyAccessor
 }
 
 type TypeSpecAccessor interface {
@@ -54,6 +56,9 @@ type TypeSpecAccessor interface {
 	FQBaseName() FQTypeNameAccessor
 	String() string
 	Equal(accessor TypeSpecAccessor) bool
+	DeclaredProperties() []TypeSpecPropertyAccessor
+	Properties() []TypeSpecPropertyAccessor
+	Property(name string) TypeSpecPropertyAccessor
 }
 
 func NewFQTypeName(name string, namespace string) *FQTypeName {
@@ -117,6 +122,57 @@ func (t *TypeSpec) FQBaseName() FQTypeNameAccessor {
 	return t.base.FQName()
 }
 
+func (t *TypeSpec) SetProperties(properties ...TypeSpecPropertyAccessor) *TypeSpec {
+	t.properties = properties
+	return t
+}
+
+func (t *TypeSpec) DeclaredProperties() []TypeSpecPropertyAccessor {
+	return t.properties
+}
+
+func (t *TypeSpec) Properties() []TypeSpecPropertyAccessor {
+	if t.base == nil {
+		return t.properties
+	}
+
+	inherited := t.base.Properties()
+	if len(t.properties) == 0 {
+		return inherited
+	}
+	properties := make([]TypeSpecPropertyAccessor, 0, len(inherited)+len(t.properties))
+	for _, p := range inherited {
+		if declaredProperty(t.properties, p.Name()) == nil {
+			properties = append(properties, p)
+		}
+	}
+	return append(properties, t.properties...)
+}
+
+func (t *TypeSpec) Property(name string) TypeSpecPropertyAccessor {
+	if p := declaredProperty(t.properties, name); p != nil {
+		return p
+	}
+	for _, p := range t.properties {
+		if p.ChoiceTypeSpec(name) != nil {
+			return p
+		}
+	}
+	if t.base == nil {
+		return nil
+	}
+	return t.base.Property(name)
+}
+
+func declaredProperty(properties []TypeSpecPropertyAccessor, name string) TypeSpecPropertyAccessor {
+	for _, p := range properties {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
 func (t *TypeSpec) String() string {
 	if t.fqName == nil {
 		return ""
@@ -0,0 +1,142 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type PropertyFlags int
+
+const (
+	ModifierPropertyFlag PropertyFlags = 1 << iota
+	SummaryPropertyFlag
+)
+
+const UnboundedCardinality = -1
+
+const choicePropertySuffix = "[x]"
+
+type TypeSpecProperty struct {
+	name      string
+	typeSpecs []TypeSpecAccessor
+	min       int
+	max       int
+	choice    bool
+	flags     PropertyFlags
+}
+
+type TypeSpecPropertyAccessor interface {
+	Name() string
+	TypeSpecs() []TypeSpecAccessor
+	Min() int
+	Max() int
+	Many() bool
+	Choice() bool
+	Modifier() bool
+	Summary() bool
+	ChoiceTypeSpec(name string) TypeSpecAccessor
+}
+
+func NewTypeSpecProperty(name string, min int, max int, flags PropertyFlags,
+	typeSpecs ...TypeSpecAccessor) *TypeSpecProperty {
+	choice := strings.HasSuffix(name, choicePropertySuffix)
+	if choice {
+		name = name[:len(name)-len(choicePropertySuffix)]
+	}
+
+	return &TypeSpecProperty{
+		name:      name,
+		typeSpecs: typeSpecs,
+		min:       min,
+		max:       max,
+		choice:    choice || len(typeSpecs) > 1,
+		flags:     flags,
+	}
+}
+
+func newPrimitiveValueProperty(systemTypeSpec TypeSpecAccessor) *TypeSpecProperty {
+	return NewTypeSpecProperty("value", 0, 1, 0, systemTypeSpec)
+}
+
+func (p *TypeSpecProperty) Name() string {
+	return p.name
+}
+
+func (p *TypeSpecProperty) TypeSpecs() []TypeSpecAccessor {
+	return p.typeSpecs
+}
+
+func (p *TypeSpecProperty) Min() int {
+	return p.min
+}
+
+func (p *TypeSpecProperty) Max() int {
+	return p.max
+}
+
+func (p *TypeSpecProperty) Many() bool {
+	return p.max == UnboundedCardinality || p.max > 1
+}
+
+func (p *TypeSpecProperty) Choice() bool {
+	return p.choice
+}
+
+func (p *TypeSpecProperty) Modifier() bool {
+	return p.flags&ModifierPropertyFlag != 0
+}
+
+func (p *TypeSpecProperty) Summary() bool {
+	return p.flags&SummaryPropertyFlag != 0
+}
+
+func (p *TypeSpecProperty) ChoiceTypeSpec(name string) TypeSpecAccessor {
+	if !p.choice || len(name) <= len(p.name) || !strings.HasPrefix(name, p.name) {
+		return nil
+	}
+
+	suffix := name[len(p.name):]
+	for _, spec := range p.typeSpecs {
+		if spec.FQName() != nil && choiceTypeSuffix(spec.FQName().Name()) == suffix {
+			return spec
+		}
+	}
+	return nil
+}
+
+func choiceTypeSuffix(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	if size == 0 {
+		return ""
+	}
+	return string(unicode.ToUpper(r)) + name[size:]
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewTypeSpecProperty(t *testing.T) {
+	p := NewTypeSpecProperty("given", 1, UnboundedCardinality, SummaryPropertyFlag, stringTypeSpec)
+	assert.Equal(t, "given", p.Name())
+	assert.Equal(t, []TypeSpecAccessor{stringTypeSpec}, p.TypeSpecs())
+	assert.Equal(t, 1, p.Min())
+	assert.Equal(t, UnboundedCardinality, p.Max())
+	assert.True(t, p.Many())
+	assert.False(t, p.Choice())
+	assert.False(t, p.Modifier())
+	assert.True(t, p.Summary())
+}
+
+func TestNewTypeSpecPropertyModifier(t *testing.T) {
+	p := NewTypeSpecProperty("status", 1, 1, ModifierPropertyFlag, codeTypeSpec)
+	assert.False(t, p.Many())
+	assert.True(t, p.Modifier())
+	assert.False(t, p.Summary())
+}
+
+func TestNewTypeSpecPropertyMax(t *testing.T) {
+	assert.True(t, NewTypeSpecProperty("a", 0, 2, 0, stringTypeSpec).Many())
+	assert.False(t, NewTypeSpecProperty("a", 0, 0, 0, stringTypeSpec).Many())
+}
+
+func TestNewTypeSpecPropertyChoice(t *testing.T) {
+	p := NewTypeSpecProperty("value[x]", 0, 1, 0, quantityTypeSpec, dateTimeTypeSpec)
+	assert.Equal(t, "value", p.Name())
+	assert.True(t, p.Choice())
+	assert.Len(t, p.TypeSpecs(), 2)
+}
+
+func TestNewTypeSpecPropertyChoiceSingleType(t *testing.T) {
+	p := NewTypeSpecProperty("effective[x]", 0, 1, 0, dateTimeTypeSpec)
+	assert.Equal(t, "effective", p.Name())
+	assert.True(t, p.Choice())
+}
+
+func TestNewTypeSpecPropertyChoiceMultipleTypes(t *testing.T) {
+	p := NewTypeSpecProperty("value", 0, 1, 0, quantityTypeSpec, dateTimeTypeSpec)
+	assert.Equal(t, "value", p.Name())
+	assert.True(t, p.Choice())
+}
+
+func TestTypeSpecPropertyChoiceTypeSpec(t *testing.T) {
+	p := NewTypeSpecProperty("value[x]", 0, 1, 0, quantityTypeSpec, dateTimeTypeSpec)
+	assert.Same(t, quantityTypeSpec, p.ChoiceTypeSpec("valueQuantity"))
+	assert.Same(t, dateTimeTypeSpec, p.ChoiceTypeSpec("valueDateTime"))
+	assert.Nil(t, p.ChoiceTypeSpec("valueString"))
+	assert.Nil(t, p.ChoiceTypeSpec("valuedateTime"))
+	assert.Nil(t, p.ChoiceTypeSpec("value"))
+	assert.Nil(t, p.ChoiceTypeSpec("otherQuantity"))
+}
+
+func TestTypeSpecPropertyChoiceTypeSpecNoChoice(t *testing.T) {
+	p := NewTypeSpecProperty("value", 0, 1, 0, quantityTypeSpec)
+	assert.Nil(t, p.ChoiceTypeSpec("valueQuantity"))
+}
+
+func TestTypeSpecPropertyChoiceTypeSpecNoName(t *testing.T) {
+	p := NewTypeSpecProperty("value[x]", 0, 1, 0, NewTypeSpec(nil), NewTypeSpec(NewFQTypeName("", "")))
+	assert.Nil(t, p.ChoiceTypeSpec("value"))
+}
+
+func TestElementTypeSpecProperties(t *testing.T) {
+	properties := elementTypeSpec.Properties()
+	if assert.Len(t, properties, 2) {
+		assert.Equal(t, "id", properties[0].Name())
+		assert.Equal(t, []TypeSpecAccessor{systemStringTypeSpec}, properties[0].TypeSpecs())
+		assert.Equal(t, "extension", properties[1].Name())
+		assert.Equal(t, []TypeSpecAccessor{extensionTypeSpec}, properties[1].TypeSpecs())
+		assert.Equal(t, 0, properties[1].Min())
+		assert.Equal(t, UnboundedCardinality, properties[1].Max())
+	}
+}
+
+func TestExtensionTypeSpecProperties(t *testing.T) {
+	spec := LookupTypeSpec("FHIR.Extension")
+	if assert.NotNil(t, spec) {
+		url := spec.Property("url")
+		if assert.NotNil(t, url) {
+			assert.Equal(t, 1, url.Min())
+			assert.Equal(t, 1, url.Max())
+		}
+		value := spec.Property("valueQuantity")
+		if assert.NotNil(t, value) {
+			assert.Equal(t, "value", value.Name())
+			assert.True(t, value.Choice())
+			assert.Same(t, quantityTypeSpec, value.ChoiceTypeSpec("valueQuantity"))
+		}
+		assert.NotNil(t, spec.Property("extension"))
+	}
+}
+
+func TestPrimitiveTypeSpecValueProperties(t *testing.T) {
+	tests := []struct {
+		spec   TypeSpecAccessor
+		system TypeSpecAccessor
+	}{
+		{booleanTypeSpec, systemBooleanTypeSpec},
+		{integerTypeSpec, systemIntegerTypeSpec},
+		{positiveIntTypeSpec, systemIntegerTypeSpec},
+		{unsignedIntTypeSpec, systemIntegerTypeSpec},
+		{stringTypeSpec, systemStringTypeSpec},
+		{codeTypeSpec, systemStringTypeSpec},
+		{idTypeSpec, systemStringTypeSpec},
+		{markdownTypeSpec, systemStringTypeSpec},
+		{uriTypeSpec, systemStringTypeSpec},
+		{decimalTypeSpec, systemDecimalTypeSpec},
+		{dateTypeSpec, systemDateTypeSpec},
+		{dateTimeTypeSpec, systemDateTimeTypeSpec},
+		{timeTypeSpec, systemTimeTypeSpec},
+	}
+	for _, test := range tests {
+		value := test.spec.Property("value")
+		if assert.NotNil(t, value, "value property expected for %s", test.spec) {
+			assert.Equal(t, []TypeSpecAccessor{test.system}, value.TypeSpecs())
+			assert.Equal(t, 0, value.Min())
+			assert.Equal(t, 1, value.Max())
+		}
+		assert.Len(t, test.spec.Properties(), 3)
+	}
+}
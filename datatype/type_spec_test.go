@@ -136,3 +136,43 @@ func TestCommonBaseTypeNone(t *testing.T) {
 	other := NewTypeSpec(NewFQTypeName("Other", ""))
 	assert.Nil(t, CommonBaseType(patient, other), "no common base type expected")
 }
+
+func TestTypeSpecProperties(t *testing.T) {
+	id := NewTypeSpecProperty("id", 0, 1, 0, stringTypeSpec)
+	name := NewTypeSpecProperty("name", 0, 1, 0, stringTypeSpec)
+	base := NewTypeSpec(NewFQTypeName("Base", "Test")).SetProperties(id, name)
+	other := NewTypeSpecProperty("name", 1, 1, 0, codeTypeSpec)
+	value := NewTypeSpecProperty("value[x]", 0, 1, 0, stringTypeSpec, integerTypeSpec)
+	ti := NewTypeSpecWithBase(NewFQTypeName("Test", "Test"), base).SetProperties(other, value)
+
+	assert.Equal(t, []TypeSpecPropertyAccessor{other, value}, ti.DeclaredProperties())
+	assert.Equal(t, []TypeSpecPropertyAccessor{id, other, value}, ti.Properties())
+	assert.Equal(t, []TypeSpecPropertyAccessor{id, name}, base.Properties())
+}
+
+func TestTypeSpecPropertiesInherited(t *testing.T) {
+	id := NewTypeSpecProperty("id", 0, 1, 0, stringTypeSpec)
+	base := NewTypeSpec(NewFQTypeName("Base", "Test")).SetProperties(id)
+	ti := NewTypeSpecWithBase(NewFQTypeName("Test", "Test"), base)
+
+	assert.Nil(t, ti.DeclaredProperties())
+	assert.Equal(t, []TypeSpecPropertyAccessor{id}, ti.Properties())
+}
+
+func TestTypeSpecPropertiesNone(t *testing.T) {
+	assert.Nil(t, NewTypeSpec(NewFQTypeName("Test", "Test")).Properties())
+}
+
+func TestTypeSpecProperty(t *testing.T) {
+	id := NewTypeSpecProperty("id", 0, 1, 0, stringTypeSpec)
+	base := NewTypeSpec(NewFQTypeName("Base", "Test")).SetProperties(id)
+	value := NewTypeSpecProperty("value[x]", 0, 1, 0, stringTypeSpec, integerTypeSpec)
+	ti := NewTypeSpecWithBase(NewFQTypeName("Test", "Test"), base).SetProperties(value)
+
+	assert.Same(t, id, ti.Property("id"))
+	assert.Same(t, value, ti.Property("value"))
+	assert.Same(t, value, ti.Property("valueInteger"))
+	assert.Nil(t, ti.Property("valueBoolean"))
+	assert.Nil(t, ti.Property("other"))
+	assert.Nil(t, base.Property("other"))
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:generate go run gen_unicode_tables.go -version 15.0.0 -data UnicodeData.txt -exclusions CompositionExclusions.txt
+
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+type combiningClassRange struct {
+	first rune
+	last  rune
+	class uint8
+}
+
+func NormalizeNFD(value string) string {
+	if isASCII(value) {
+		return value
+	}
+	return string(decomposeCanonical(value))
+}
+
+func NormalizeNFC(value string) string {
+	if isASCII(value) {
+		return value
+	}
+	return string(composeCanonical(decomposeCanonical(value)))
+}
+
+func RemoveDiacritics(value string) string {
+	if isASCII(value) {
+		return value
+	}
+
+	runes := decomposeCanonical(value)
+	result := runes[:0]
+	for _, c := range runes {
+		if !unicode.Is(unicode.Mn, c) {
+			result = append(result, c)
+		}
+	}
+	return string(composeCanonical(result))
+}
+
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func combiningClass(c rune) uint8 {
+	if c < 0x0300 {
+		return 0
+	}
+	i := sort.Search(len(combiningClassRanges), func(i int) bool {
+		return combiningClassRanges[i].last >= c
+	})
+	if i < len(combiningClassRanges) && combiningClassRanges[i].first <= c {
+		return combiningClassRanges[i].class
+	}
+	return 0
+}
+
+func decomposeCanonical(value string) []rune {
+	result := make([]rune, 0, len(value))
+	for _, c := range value {
+		if d, found := canonicalDecompositions[c]; found {
+			for _, dc := range d {
+				result = append(result, dc)
+			}
+		} else if s := c - hangulSBase; s >= 0 && s < hangulSCount {
+			result = append(result, hangulLBase+s/hangulNCount, hangulVBase+(s%hangulNCount)/hangulTCount)
+			if t := s % hangulTCount; t != 0 {
+				result = append(result, hangulTBase+t)
+			}
+		} else {
+			result = append(result, c)
+		}
+	}
+
+	for i := 1; i < len(result); i++ {
+		class := combiningClass(result[i])
+		for j := i; class != 0 && j > 0 && combiningClass(result[j-1]) > class; j-- {
+			result[j-1], result[j] = result[j], result[j-1]
+		}
+	}
+	return result
+}
+
+func composeCanonical(runes []rune) []rune {
+	if len(runes) == 0 {
+		return runes
+	}
+
+	result := runes[:1]
+	starter := 0
+	lastClass := int(combiningClass(runes[0]))
+	if lastClass != 0 {
+		lastClass = 256
+	}
+	for _, c := range runes[1:] {
+		class := int(combiningClass(c))
+		if lastClass == 0 || lastClass < class {
+			if composite, found := composeCanonicalPair(result[starter], c); found {
+				result[starter] = composite
+				continue
+			}
+		}
+		if class == 0 {
+			starter = len(result)
+		}
+		lastClass = class
+		result = append(result, c)
+	}
+	return result
+}
+
+func composeCanonicalPair(first rune, second rune) (rune, bool) {
+	if l, v := first-hangulLBase, second-hangulVBase; l >= 0 && l < hangulLCount && v >= 0 && v < hangulVCount {
+		return hangulSBase + (l*hangulVCount+v)*hangulTCount, true
+	}
+	if s, t := first-hangulSBase, second-hangulTBase; s >= 0 && s < hangulSCount && s%hangulTCount == 0 &&
+		t > 0 && t < hangulTCount {
+		return first + t, true
+	}
+	composite, found := canonicalCompositions[[2]rune{first, second}]
+	return composite, found
+}
+
+func equivalenceKey(value string) string {
+	var b strings.Builder
+	b.Grow(len(value))
+	ws := false
+	for _, c := range decomposeCanonical(value) {
+		switch {
+		case unicode.IsSpace(c):
+			ws = b.Len() > 0
+		case unicode.Is(unicode.Mn, c):
+		default:
+			if ws {
+				b.WriteByte(' ')
+				ws = false
+			}
+			b.WriteRune(unicode.ToLower(c))
+		}
+	}
+	return b.String()
+}
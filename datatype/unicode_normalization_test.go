@@ -0,0 +1,108 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package datatype
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeNFD(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"Test", "Test"},
+		{"Jos\u00E9", "Jose\u0301"},
+		{"\u1E08", "C\u0327\u0301"},
+		{"\u212B", "A\u030A"},
+		{"a\u0301\u0323", "a\u0323\u0301"},
+		{"\uAC01", "\u1100\u1161\u11A8"},
+		{"\U0001D15E", "\U0001D157\U0001D165"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeNFD(test.value), "unexpected NFD of %q", test.value)
+	}
+}
+
+func TestNormalizeNFC(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"Test", "Test"},
+		{"Jose\u0301", "Jos\u00E9"},
+		{"C\u0327\u0301", "\u1E08"},
+		{"C\u0301\u0327", "\u1E08"},
+		{"\u212B", "\u00C5"},
+		{"a\u0323\u0301", "\u1EA1\u0301"},
+		{"\u1100\u1161\u11A8", "\uAC01"},
+		{"\u0958", "\u0915\u093C"},
+		{"\u0301a", "\u0301a"},
+		{"\U0001D157\U0001D165", "\U0001D157\U0001D165"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, NormalizeNFC(test.value), "unexpected NFC of %q", test.value)
+	}
+}
+
+func TestNormalizeEmpty(t *testing.T) {
+	assert.Equal(t, "", NormalizeNFC(""))
+	assert.Equal(t, "", NormalizeNFD(""))
+	assert.Equal(t, "", RemoveDiacritics(""))
+}
+
+func TestRemoveDiacritics(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"Test", "Test"},
+		{"Jos\u00E9", "Jose"},
+		{"M\u00FCller-L\u00FCdenscheidt", "Muller-Ludenscheidt"},
+		{"\u00C5ngstr\u00F6m", "Angstrom"},
+		{"Stra\u00DFe", "Stra\u00DFe"},
+		{"\uAC01", "\uAC01"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, RemoveDiacritics(test.value), "unexpected result for %q", test.value)
+	}
+}
+
+func TestCombiningClass(t *testing.T) {
+	assert.Equal(t, uint8(0), combiningClass('a'))
+	assert.Equal(t, uint8(230), combiningClass(0x0301))
+	assert.Equal(t, uint8(220), combiningClass(0x0323))
+	assert.Equal(t, uint8(202), combiningClass(0x0327))
+	assert.Equal(t, uint8(0), combiningClass(0x10FFFF))
+}
+
+func TestUnicodeTablesVersion(t *testing.T) {
+	assert.Equal(t, "15.0.0", unicodeTablesVersion)
+}
@@ -28,6 +28,8 @@
 
 package datatype
 
+import "strconv"
+
 var unsignedIntTypeSpec = newElementTypeSpecWithBase("unsignedInt", integerTypeSpec)
 
 type unsignedIntType struct {
@@ -44,11 +46,19 @@ func NewUnsignedIntNil() UnsignedIntAccessor {
 
 func NewUnsignedInt(value int32) UnsignedIntAccessor {
 	if value < 0 {
-		panic("datatype: unsigned int must not be negative")
+		panic(NewParseError(UnsignedIntDataType, strconv.Itoa(int(value)), 0, OutOfRangeParseErrorReason))
 	}
 	return newUnsignedInt(false, value)
 }
 
+func ParseUnsignedInt(value string) (UnsignedIntAccessor, error) {
+	if i, err := parseStrictInteger(UnsignedIntDataType, value, false); err != nil {
+		return nil, err
+	} else {
+		return newUnsignedInt(false, i), nil
+	}
+}
+
 func newUnsignedInt(nilValue bool, value int32) UnsignedIntAccessor {
 	return &unsignedIntType{
 		integerType{
@@ -30,6 +30,7 @@ package datatype
 
 import (
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -109,3 +110,40 @@ func TestUnsignedIntEqualInteger(t *testing.T) {
 	assert.Equal(t, true, NewUnsignedInt(8274).Equal(NewInteger(8274)))
 	assert.Equal(t, true, NewUnsignedInt(8274).Equivalent(NewInteger(8274)))
 }
+
+func TestUnsignedIntInvalidPanicsWithParseError(t *testing.T) {
+	defer func() {
+		assertParseError(t, recover().(error), UnsignedIntDataType, 0, OutOfRangeParseErrorReason)
+	}()
+	NewUnsignedInt(-1)
+}
+
+func TestParseUnsignedInt(t *testing.T) {
+	for value, expected := range map[string]int32{"0": 0, "1": 1, "2147483647": math.MaxInt32} {
+		o, err := ParseUnsignedInt(value)
+		if assert.NoError(t, err, "no error expected for %s", value) {
+			assert.Equal(t, UnsignedIntDataType, o.DataType())
+			assert.Equal(t, expected, o.Int())
+		}
+	}
+}
+
+func TestParseUnsignedIntInvalid(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		reason   ParseErrorReasons
+	}{
+		{"", 0, InvalidSyntaxParseErrorReason},
+		{"-1", 0, InvalidSyntaxParseErrorReason},
+		{"+1", 0, InvalidSyntaxParseErrorReason},
+		{"01", 0, InvalidSyntaxParseErrorReason},
+		{"1.0", 1, InvalidSyntaxParseErrorReason},
+		{"2147483648", 0, OutOfRangeParseErrorReason},
+	}
+	for _, test := range tests {
+		o, err := ParseUnsignedInt(test.value)
+		assert.Nil(t, o, "value unexpected")
+		assertParseError(t, err, UnsignedIntDataType, test.position, test.reason)
+	}
+}
@@ -29,11 +29,11 @@
 package datatype
 
 import (
-	"fmt"
 	"regexp"
 )
 
-var uriTypeSpec = newElementTypeSpec("uri")
+var uriTypeSpec = newElementTypeSpec("uri").SetProperties(
+	newPrimitiveValueProperty(systemStringTypeSpec))
 
 var uriRegexp = regexp.MustCompile("^\\S*$")
 
@@ -62,18 +62,27 @@ func NewURINil() URIAccessor {
 
 func NewURI(value string) URIAccessor {
 	if !uriRegexp.MatchString(value) {
-		panic(fmt.Sprintf("not a valid URI: %s", value))
+		panic(newSyntaxParseError(URIDataType, value, uriErrorPosition(value)))
 	}
 	return newURI(false, value)
 }
 
 func ParseURI(value string) (URIAccessor, error) {
 	if !uriRegexp.MatchString(value) {
-		return nil, fmt.Errorf("not a valid URI: %s", value)
+		return nil, newSyntaxParseError(URIDataType, value, uriErrorPosition(value))
 	}
 	return newURI(false, value), nil
 }
 
+func uriErrorPosition(value string) int {
+	for pos := 0; pos < len(value); pos++ {
+		if isRegexpSpace(value[pos]) {
+			return pos
+		}
+	}
+	return 0
+}
+
 func newURI(nilValue bool, value string) URIAccessor {
 	return &uriType{
 		PrimitiveType: PrimitiveType{
@@ -127,3 +127,8 @@ func TestURIEqualNotEqual(t *testing.T) {
 func TestURIEqualNotEquivalent(t *testing.T) {
 	assert.Equal(t, false, NewURI("TEST").Equivalent(NewURI("test")))
 }
+
+func TestParseURIInvalidPosition(t *testing.T) {
+	_, err := ParseURI("urn:test value")
+	assertParseError(t, err, URIDataType, 8, InvalidSyntaxParseErrorReason)
+}
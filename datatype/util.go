@@ -31,33 +31,10 @@ package datatype
 import (
 	"strconv"
 	"strings"
-	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
-type normalizedStringStream struct {
-	value []rune
-	pos   int
-	size  int
-}
-
-func (s *normalizedStringStream) next() (rune, bool) {
-	begin := s.pos == 0
-	ws := false
-	for s.pos < s.size {
-		c := s.value[s.pos]
-		if c == ' ' || c == '\r' || c == '\n' || c == '\t' {
-			s.pos = s.pos + 1
-			ws = true
-		} else if ws && !begin {
-			return ' ', true
-		} else {
-			s.pos = s.pos + 1
-			return unicode.ToLower(c), true
-		}
-	}
-	return 0, false
-}
-
 func NormalizedStringEqual(value1 string, value2 string) bool {
 	if value1 == value2 {
 		return true
@@ -65,27 +42,116 @@ func NormalizedStringEqual(value1 string, value2 string) bool {
 	if len(value1) == 0 || len(value2) == 0 {
 		return false
 	}
+	return equivalenceKey(value1) == equivalenceKey(value2)
+}
 
-	r1 := []rune(value1)
-	s1 := normalizedStringStream{value: r1, size: len(r1)}
-	r2 := []rune(value2)
-	s2 := normalizedStringStream{value: r2, size: len(r2)}
+func isRegexpSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
 
-	for c1, ok1 := s1.next(); ok1; c1, ok1 = s1.next() {
-		c2, ok2 := s2.next()
-		if !ok2 || c1 != c2 {
-			return false
+func writeStringBuilderInt(b *strings.Builder, value int, digits int) {
+	formatted := strconv.FormatInt(int64(value), 10)
+	l := len(formatted)
+	for i := l; i < digits; i++ {
+		b.WriteByte('0')
+	}
+	b.WriteString(formatted)
+}
+
+func writeStringBuilderEscaped(b *strings.Builder, value string) {
+	for _, c := range value {
+		switch c {
+		case '\'', '"', '`', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case '\f':
+			b.WriteString("\\f")
+		case '\n':
+			b.WriteString("\\n")
+		case '\r':
+			b.WriteString("\\r")
+		case '\t':
+			b.WriteString("\\t")
+		default:
+			if c < 0x20 {
+				b.WriteString("\\u")
+				writeStringBuilderHex(b, int(c), 4)
+			} else {
+				b.WriteRune(c)
+			}
 		}
 	}
-	_, ok := s2.next()
-	return !ok
 }
 
-func writeStringBuilderInt(b *strings.Builder, value int, digits int) {
-	formatted := strconv.FormatInt(int64(value), 10)
+func writeStringBuilderHex(b *strings.Builder, value int, digits int) {
+	formatted := strconv.FormatInt(int64(value), 16)
 	l := len(formatted)
 	for i := l; i < digits; i++ {
 		b.WriteByte('0')
 	}
 	b.WriteString(formatted)
 }
+
+func unescapeStringLiteral(value string) (string, bool) {
+	if strings.IndexByte(value, '\\') < 0 {
+		return value, true
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(value) {
+			return "", false
+		}
+		switch value[i] {
+		case '\'', '"', '`', '\\', '/':
+			b.WriteByte(value[i])
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			r, ok := parseUnicodeEscape(value, i)
+			if !ok {
+				return "", false
+			}
+			i += 4
+			if utf16.IsSurrogate(r) && i+2 < len(value) && value[i+1] == '\\' && value[i+2] == 'u' {
+				if low, ok := parseUnicodeEscape(value, i+2); ok {
+					if combined := utf16.DecodeRune(r, low); combined != utf8.RuneError {
+						r = combined
+						i += 6
+					}
+				}
+			}
+			if !utf8.ValidRune(r) {
+				return "", false
+			}
+			b.WriteRune(r)
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+func parseUnicodeEscape(value string, i int) (rune, bool) {
+	if i+4 >= len(value) {
+		return 0, false
+	}
+	r, err := strconv.ParseUint(value[i+1:i+5], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(r), true
+}
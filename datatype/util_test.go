@@ -73,3 +73,76 @@ func TestWriteStringBuilderIntSufficientDigits(t *testing.T) {
 	writeStringBuilderInt(&b, 8724, 4)
 	assert.Equal(t, "8724", b.String())
 }
+
+func TestWriteStringBuilderEscaped(t *testing.T) {
+	var b strings.Builder
+	writeStringBuilderEscaped(&b, "a'b\"c`d\\e\ff\ng\rh\ti\x01j")
+	assert.Equal(t, "a\\'b\\\"c\\`d\\\\e\\ff\\ng\\rh\\ti\\u0001j", b.String())
+}
+
+func TestUnescapeStringLiteral(t *testing.T) {
+	v, ok := unescapeStringLiteral("a\\'b\\\"c\\`d\\\\e\\/f\\fg\\nh\\ri\\tj\\u00e9")
+	assert.True(t, ok, "valid string literal expected")
+	assert.Equal(t, "a'b\"c`d\\e/f\fg\nh\ri\tj\u00e9", v)
+}
+
+func TestUnescapeStringLiteralUnescaped(t *testing.T) {
+	v, ok := unescapeStringLiteral("test")
+	assert.True(t, ok, "valid string literal expected")
+	assert.Equal(t, "test", v)
+}
+
+func TestUnescapeStringLiteralTrailingBackslash(t *testing.T) {
+	_, ok := unescapeStringLiteral("test\\")
+	assert.False(t, ok, "invalid string literal expected")
+}
+
+func TestUnescapeStringLiteralInvalidEscape(t *testing.T) {
+	_, ok := unescapeStringLiteral("te\\xst")
+	assert.False(t, ok, "invalid string literal expected")
+}
+
+func TestUnescapeStringLiteralSurrogatePair(t *testing.T) {
+	v, ok := unescapeStringLiteral("a\\uD83D\\uDE00b")
+	if assert.True(t, ok) {
+		assert.Equal(t, "a\U0001F600b", v)
+	}
+}
+
+func TestUnescapeStringLiteralUnpairedSurrogate(t *testing.T) {
+	_, ok := unescapeStringLiteral("\\uD83D")
+	assert.False(t, ok)
+	_, ok = unescapeStringLiteral("\\uD83Dx")
+	assert.False(t, ok)
+	_, ok = unescapeStringLiteral("\\uD83D\\u0041")
+	assert.False(t, ok)
+	_, ok = unescapeStringLiteral("\\uDE00\\uD83D")
+	assert.False(t, ok)
+}
+
+func TestUnescapeStringLiteralInvalidUnicode(t *testing.T) {
+	_, ok := unescapeStringLiteral("\\u00g1")
+	assert.False(t, ok, "invalid string literal expected")
+	_, ok = unescapeStringLiteral("\\u00e")
+	assert.False(t, ok, "invalid string literal expected")
+}
+
+func TestNormalizedStringEqualDiacritics(t *testing.T) {
+	assert.Equal(t, true, NormalizedStringEqual("Jos\u00E9", "jose"))
+	assert.Equal(t, true, NormalizedStringEqual("Jose\u0301", "JOS\u00C9"))
+	assert.Equal(t, true, NormalizedStringEqual("M\u00FCller", "muller"))
+}
+
+func TestNormalizedStringEqualUnicodeWhitespace(t *testing.T) {
+	assert.Equal(t, true, NormalizedStringEqual("Test\u00A0\u2003Under\u3000", "test under"))
+	assert.Equal(t, true, NormalizedStringEqual("\u0085Test\u2028Under", "test under"))
+}
+
+func TestNormalizedStringEqualWhitespaceOnly(t *testing.T) {
+	assert.Equal(t, false, NormalizedStringEqual("Test", "Te st"))
+	assert.Equal(t, true, NormalizedStringEqual(" ", "\u00A0"))
+}
+
+func TestNormalizedStringEqualDifferentLetters(t *testing.T) {
+	assert.Equal(t, false, NormalizedStringEqual("Stra\u00DFe", "Strase"))
+}
@@ -82,7 +82,12 @@ func (r *DynamicResource) ResourceType() string {
 }
 
 func (r *DynamicResource) TypeSpec() datatype.TypeSpecAccessor {
-	return datatype.NewTypeSpecWithBase(datatype.NewFQTypeName(r.ResourceType(), ""), resourceTypeSpec)
+	resourceType := r.ResourceType()
+	if spec := datatype.LookupTypeSpec(datatype.FHIRNamespaceName + "." + resourceType); spec != nil &&
+		datatype.IsSubtypeOf(spec, resourceTypeSpec) {
+		return spec
+	}
+	return datatype.NewTypeSpecWithBase(datatype.NewFQTypeName(resourceType, ""), resourceTypeSpec)
 }
 
 func (r *DynamicResource) Equal(accessor datatype.Accessor) bool {
@@ -130,3 +130,20 @@ func TestDynamicResourceEqualIdDiffers(t *testing.T) {
 	assert.Equal(t, false, r1.Equal(r2), "same model must equal")
 	assert.Equal(t, true, r1.Equivalent(r2), "same model must equal")
 }
+
+func TestDynamicResourceRegisteredTypeSpec(t *testing.T) {
+	domainResource := datatype.NewTypeSpecWithBase(
+		datatype.NewFQTypeName("TestDomainResource", datatype.FHIRNamespaceName), resourceTypeSpec)
+	spec := datatype.LoadOrRegisterTypeSpec(datatype.NewTypeSpecWithBase(
+		datatype.NewFQTypeName("TestRegisteredResource", datatype.FHIRNamespaceName), domainResource))
+
+	i := NewDynamicResource("TestRegisteredResource").TypeSpec()
+	assert.Same(t, spec, i)
+	assert.True(t, datatype.IsSubtypeOf(i, resourceTypeSpec), "resource type expected")
+}
+
+func TestDynamicResourceRegisteredNonResourceTypeSpec(t *testing.T) {
+	i := NewDynamicResource("Quantity").TypeSpec()
+	assert.Equal(t, "Quantity", i.String())
+	assert.Equal(t, "FHIR.Resource", i.Base().String())
+}
@@ -30,7 +30,8 @@ package resource
 
 import "github.com/healthiop/hi/datatype"
 
-var resourceTypeSpec = datatype.NewTypeSpec(datatype.NewFQTypeName("Resource", datatype.FHIRNamespaceName))
+var resourceTypeSpec = datatype.LoadOrRegisterTypeSpec(
+	datatype.NewTypeSpec(datatype.NewFQTypeName("Resource", datatype.FHIRNamespaceName)))
 
 type Accessor interface {
 	datatype.Accessor